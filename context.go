@@ -0,0 +1,17 @@
+package valid
+
+import "context"
+
+type contextKey struct{}
+
+// FromContext returns the struct decoded and validated by ValidateRequest.
+// T is the struct type given to RequestStruct, e.g. FromContext[CreateUser](r.Context()).
+func FromContext[T any](ctx context.Context) (*T, bool) {
+	elem, ok := ctx.Value(contextKey{}).(*T)
+	return elem, ok
+}
+
+// newContext returns a copy of ctx carrying the validated struct.
+func newContext(ctx context.Context, elem any) context.Context {
+	return context.WithValue(ctx, contextKey{}, elem)
+}
@@ -0,0 +1,103 @@
+package valid
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/seyramlabs/valid/locale"
+)
+
+var ruleNameRegex = regexp.MustCompile(`^[a-zA-Z0-9_]+$`)
+
+// RuleFunc reports whether value passes a custom rule.
+// params holds the comma separated rule arguments, e.g. ["3", "8"] for "sku:3,8".
+type RuleFunc func(value reflect.Value, params []string) bool
+
+// RuleFuncCtx is a RuleFunc receiving the context of the validation, e.g. the
+// request context in ValidateRequest, Middleware and Bind.
+type RuleFuncCtx func(ctx context.Context, value reflect.Value, params []string) bool
+
+type customRule struct {
+	fn       RuleFuncCtx
+	messages map[string]string
+}
+
+// ruleRegistry holds the custom rules of a Validator. It is shared by every
+// validation context created from the Validator and is safe for concurrent use.
+type ruleRegistry struct {
+	mu    sync.RWMutex
+	rules map[string]customRule
+}
+
+func newRuleRegistry() *ruleRegistry {
+	return &ruleRegistry{rules: make(map[string]customRule)}
+}
+
+// RegisterRule registers a custom rule usable in validate tags and ValidateMap rules.
+// messages maps a locale to the message format; the first %s is the field name
+// and the following ones are the rule parameters. A custom rule shadows a
+// built-in rule with the same name.
+func (v *validation) RegisterRule(name string, fn RuleFunc, messages map[string]string) error {
+	if fn == nil {
+		return fmt.Errorf("validate: rule %q has no function", name)
+	}
+	return v.RegisterRuleCtx(name, func(_ context.Context, value reflect.Value, params []string) bool {
+		return fn(value, params)
+	}, messages)
+}
+
+// RegisterRuleCtx is like RegisterRule for rules using the context of the validation,
+// e.g. to query a service with the deadline of the request.
+func (v *validation) RegisterRuleCtx(name string, fn RuleFuncCtx, messages map[string]string) error {
+	if !ruleNameRegex.MatchString(name) {
+		return fmt.Errorf("validate: invalid rule name %q", name)
+	}
+	if fn == nil {
+		return fmt.Errorf("validate: rule %q has no function", name)
+	}
+	v.rules.mu.Lock()
+	defer v.rules.mu.Unlock()
+	v.rules.rules[name] = customRule{fn: fn, messages: messages}
+	return nil
+}
+
+// lookup returns the custom rule used by rule along with its name and parameters.
+func (r *ruleRegistry) lookup(rule string) (name string, params []string, cr customRule, ok bool) {
+	name, args, hasArgs := strings.Cut(rule, ":")
+	r.mu.RLock()
+	cr, ok = r.rules[name]
+	r.mu.RUnlock()
+	if ok && hasArgs {
+		params = strings.Split(args, ",")
+	}
+	return
+}
+
+func (r *ruleRegistry) has(rule string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	_, ok := r.rules[rule]
+	return ok
+}
+
+// message returns the custom message of rule for the given locale, walking its fallback chain.
+func (r *ruleRegistry) message(rule, tag string) (string, bool) {
+	r.mu.RLock()
+	cr, ok := r.rules[rule]
+	r.mu.RUnlock()
+	if !ok {
+		return "", false
+	}
+	for _, t := range locale.Fallbacks(tag) {
+		for key, msg := range cr.messages {
+			if locale.Normalize(key) == t {
+				return msg, true
+			}
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,232 @@
+package valid
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	// import github.com/go-sql-driver/mysql
+	_ "github.com/go-sql-driver/mysql"
+	// import github.com/lib/pq
+	_ "github.com/lib/pq"
+)
+
+// Querier runs the queries of the database rules such as unique.
+// *sql.DB, *sql.Conn and *sql.Tx implement it.
+type Querier interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// Database configuration
+type Database struct {
+	Host     string
+	Port     int
+	Name     string
+	Username string
+	Password string
+	Driver   string
+	SSLMode  string
+	// DSN is the data source name passed to the driver as is, e.g. a file for
+	// sqlite3. It is required by drivers other than postgres and mysql.
+	DSN string
+}
+
+// Open returns a connection pool to the database. The pool is reused by every
+// validation of the Validator given the Database in Config, and closed by its
+// Close method.
+func (d *Database) Open() (*sql.DB, error) {
+	if d.DSN != "" {
+		return sql.Open(d.Driver, d.DSN)
+	}
+	switch d.Driver {
+	case DriverPostgres:
+		var SSLMode string
+		if d.SSLMode != "" {
+			SSLMode = d.SSLMode
+		} else {
+			SSLMode = "disable"
+		}
+		dsn := fmt.Sprintf(
+			"host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+			d.Host,
+			d.Port,
+			d.Username,
+			d.Password,
+			d.Name,
+			SSLMode,
+		)
+		return sql.Open(d.Driver, dsn)
+	case DriverMysql:
+		dsn := fmt.Sprintf(
+			"%s:%s@tcp(%s:%d)/%s",
+			d.Username,
+			d.Password,
+			d.Host,
+			d.Port,
+			d.Name,
+		)
+		return sql.Open(d.Driver, dsn)
+	default:
+		return nil, fmt.Errorf("validate: database driver %q needs a DSN", d.Driver)
+	}
+}
+
+var identifierRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// SQLLookup is the Lookup of a SQL database. Resources are tables, possibly
+// qualified by their schema, e.g. "public.users", and fields are columns,
+// converted to snake case. The queries of Exists have the shape of
+// Dialect.ExistsQuery, e.g. for Postgres:
+//
+//	SELECT 1 FROM "table" WHERE "column"=$1 AND ... LIMIT 1
+//
+// ExistsAll selects them together, see BatchDialect.
+type SQLLookup struct {
+	DB Querier
+	// Dialect is required, lookups fail with ErrNoDialect without it.
+	Dialect Dialect
+}
+
+// Exists implements Lookup. It fails with ErrInvalidIdentifier when the table or
+// a column is not a plain identifier.
+func (l *SQLLookup) Exists(ctx context.Context, resource, field string, value any, conditions ...Condition) (bool, error) {
+	queryStr, args, err := l.query(resource, lookupConditions(field, value, conditions))
+	if err != nil {
+		return false, err
+	}
+	return rowExists(ctx, l.DB, queryStr, args...)
+}
+
+// Limits of a batched query, below those of the databases, e.g. 999 arguments
+// for older SQLite versions and 1664 selected columns for Postgres.
+const (
+	batchArgs    = 999
+	batchColumns = 500
+)
+
+// ExistsAll implements BatchLookup with one query per chunk of values, built by
+// BatchDialect.ExistsAllQuery from the Dialect.ExistsQuery of each value, e.g.
+// for Postgres:
+//
+//	SELECT CASE WHEN EXISTS (SELECT 1 FROM "table" WHERE "column"=$1 LIMIT 1) THEN 1 ELSE 0 END,
+//	CASE WHEN EXISTS (SELECT 1 FROM "table" WHERE "column"=$2 LIMIT 1) THEN 1 ELSE 0 END
+//
+// Unlike matching the rows of a "column IN (...)" query to the values in Go, the
+// database compares each value as in Exists, with the collation, padding and
+// type coercion of the column, e.g. case-insensitively on MySQL by default.
+func (l *SQLLookup) ExistsAll(ctx context.Context, resource, field string, values []any, conditions ...Condition) ([]bool, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	d, err := l.dialect()
+	if err != nil {
+		return nil, err
+	}
+	table, err := quoteIdentifier(d, resource)
+	if err != nil {
+		return nil, err
+	}
+	_, valueArgs, err := l.where(d, lookupConditions(field, values[0], conditions), nil)
+	if err != nil {
+		return nil, err
+	}
+	size := min(batchColumns, batchArgs/max(1, len(valueArgs)))
+	found := make([]bool, 0, len(values))
+	for start := 0; start < len(values); start += size {
+		chunk := values[start:min(start+size, len(values))]
+		var args []any
+		columns := make([]string, len(chunk))
+		for i, value := range chunk {
+			var where []string
+			if where, args, err = l.where(d, lookupConditions(field, value, conditions), args); err != nil {
+				return nil, err
+			}
+			columns[i] = d.ExistsQuery(table, strings.Join(where, " AND "))
+		}
+		exists := make([]bool, len(chunk))
+		dest := make([]any, len(chunk))
+		for i := range exists {
+			dest[i] = &exists[i]
+		}
+		if err := l.DB.QueryRowContext(ctx, existsAllQuery(d, columns), args...).Scan(dest...); err != nil {
+			return nil, err
+		}
+		found = append(found, exists...)
+	}
+	return found, nil
+}
+
+func (l *SQLLookup) dialect() (Dialect, error) {
+	if l.Dialect == nil {
+		return nil, fmt.Errorf("%w, set SQLLookup.Dialect", ErrNoDialect)
+	}
+	return l.Dialect, nil
+}
+
+func (l *SQLLookup) query(table string, conditions []Condition) (string, []any, error) {
+	d, err := l.dialect()
+	if err != nil {
+		return "", nil, err
+	}
+	table, err = quoteIdentifier(d, table)
+	if err != nil {
+		return "", nil, err
+	}
+	where, args, err := l.where(d, conditions, nil)
+	if err != nil {
+		return "", nil, err
+	}
+	return d.ExistsQuery(table, strings.Join(where, " AND ")), args, nil
+}
+
+// where returns the clauses of conditions, appending their values to args.
+func (l *SQLLookup) where(d Dialect, conditions []Condition, args []any) ([]string, []any, error) {
+	where := make([]string, 0, len(conditions))
+	for _, c := range conditions {
+		column, err := quoteIdentifier(d, snakeCase(c.Field))
+		if err != nil {
+			return nil, nil, err
+		}
+		switch c.Op {
+		case OpNull, OpNotNull:
+			where = append(where, fmt.Sprintf("%s %s", column, c.Op))
+		case OpEqual, OpNotEqual:
+			args = append(args, c.Value)
+			where = append(where, fmt.Sprintf("%s%s%s", column, c.Op, d.Placeholder(len(args))))
+		case OpEqualFold:
+			args = append(args, c.Value)
+			where = append(where, fmt.Sprintf("LOWER(%s)=LOWER(%s)", column, d.Placeholder(len(args))))
+		default:
+			return nil, nil, fmt.Errorf("validate: unsupported operator %q", c.Op)
+		}
+	}
+	return where, args, nil
+}
+
+// quoteIdentifier quotes the dot separated parts of name with d, e.g.
+// "public"."users" for Postgres and `public`.`users` for MySQL.
+func quoteIdentifier(d Dialect, name string) (string, error) {
+	parts := strings.Split(name, ".")
+	for i, part := range parts {
+		if !identifierRegex.MatchString(part) {
+			return "", fmt.Errorf("%w: %q", ErrInvalidIdentifier, name)
+		}
+		parts[i] = d.Quote(part)
+	}
+	return strings.Join(parts, "."), nil
+}
+
+// rowExists reports whether query returns a row.
+func rowExists(ctx context.Context, db Querier, query string, args ...any) (bool, error) {
+	var found int
+	if err := db.QueryRowContext(ctx, query, args...).Scan(&found); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,108 @@
+package valid
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Dialect describes the SQL of a database for the database rules such as unique
+// and exists. Dialects of drivers without a built-in one, e.g. sqlserver, are set
+// in Config.Dialect or registered with RegisterDialect.
+type Dialect interface {
+	// Placeholder returns the placeholder of the nth query argument, starting at 1.
+	Placeholder(n int) string
+	// Quote quotes a plain identifier such as a table, a schema or a column.
+	Quote(identifier string) string
+	// ExistsQuery returns the query selecting at most one row of table matching where.
+	ExistsQuery(table, where string) string
+}
+
+// BatchDialect is implemented by the dialects controlling the query of
+// SQLLookup.ExistsAll, e.g. to select FROM DUAL on Oracle. Other dialects use
+//
+//	SELECT CASE WHEN EXISTS (query) THEN 1 ELSE 0 END, ...
+type BatchDialect interface {
+	Dialect
+	// ExistsAllQuery returns the query selecting a single row with a column per
+	// query built by ExistsQuery, 1 when the query selects a row and 0 otherwise.
+	ExistsAllQuery(queries []string) string
+}
+
+var (
+	// Postgres is the dialect of PostgreSQL: $1 placeholders and "quoted" identifiers.
+	Postgres Dialect = sqlDialect{placeholder: numberedPlaceholder("$"), quote: `"`}
+	// MySQL is the dialect of MySQL and MariaDB: ? placeholders and `quoted` identifiers.
+	MySQL Dialect = sqlDialect{placeholder: questionPlaceholder, quote: "`"}
+	// SQLite is the dialect of SQLite: ? placeholders and "quoted" identifiers.
+	SQLite Dialect = sqlDialect{placeholder: questionPlaceholder, quote: `"`}
+)
+
+var (
+	dialectsMu sync.RWMutex
+	dialects   = map[string]Dialect{
+		DriverPostgres: Postgres,
+		"pgx":          Postgres,
+		DriverMysql:    MySQL,
+		DriverSQLite:   SQLite,
+		"sqlite":       SQLite,
+	}
+)
+
+// RegisterDialect sets the dialect of the database/sql driver name, e.g.
+// RegisterDialect("sqlserver", dialect), used by Config.Driver and Database.Driver.
+func RegisterDialect(driver string, dialect Dialect) {
+	dialectsMu.Lock()
+	defer dialectsMu.Unlock()
+	dialects[driver] = dialect
+}
+
+// DialectFor returns the dialect of the database/sql driver name.
+func DialectFor(driver string) (Dialect, bool) {
+	dialectsMu.RLock()
+	defer dialectsMu.RUnlock()
+	dialect, ok := dialects[driver]
+	return dialect, ok
+}
+
+// sqlDialect is a Dialect using LIMIT 1 to select a single row.
+type sqlDialect struct {
+	placeholder func(n int) string
+	quote       string
+}
+
+func (d sqlDialect) Placeholder(n int) string {
+	return d.placeholder(n)
+}
+
+func (d sqlDialect) Quote(identifier string) string {
+	return d.quote + strings.ReplaceAll(identifier, d.quote, d.quote+d.quote) + d.quote
+}
+
+func (d sqlDialect) ExistsQuery(table, where string) string {
+	return fmt.Sprintf("SELECT 1 FROM %s WHERE %s LIMIT 1", table, where)
+}
+
+// existsAllQuery returns the query of SQLLookup.ExistsAll selecting whether
+// each of queries selects a row.
+func existsAllQuery(d Dialect, queries []string) string {
+	if bd, ok := d.(BatchDialect); ok {
+		return bd.ExistsAllQuery(queries)
+	}
+	columns := make([]string, len(queries))
+	for i, query := range queries {
+		columns[i] = fmt.Sprintf("CASE WHEN EXISTS (%s) THEN 1 ELSE 0 END", query)
+	}
+	return "SELECT " + strings.Join(columns, ", ")
+}
+
+func numberedPlaceholder(prefix string) func(n int) string {
+	return func(n int) string {
+		return prefix + strconv.Itoa(n)
+	}
+}
+
+func questionPlaceholder(int) string {
+	return "?"
+}
@@ -0,0 +1,230 @@
+package valid
+
+import (
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrNotPointer is returned when validation is given a non pointer value.
+	ErrNotPointer = errors.New("validate: a pointer is expected as an argument")
+	// ErrNotStruct is returned when validation is given a pointer to a non struct value.
+	ErrNotStruct = errors.New("validate: a struct pointer is expected as an argument")
+	// ErrCanceled is returned along with the context error when the context of a
+	// validation is canceled or exceeds its deadline before the validation completes.
+	ErrCanceled = errors.New("validate: validation canceled")
+	// ErrNoDatabase is returned when a database rule such as unique is used
+	// without Config.DB or Config.Querier.
+	ErrNoDatabase = errors.New("validate: no database configured")
+	// ErrInvalidIdentifier is returned when a database rule names a table or a
+	// column that is not a plain SQL identifier.
+	ErrInvalidIdentifier = errors.New("validate: invalid SQL identifier")
+	// ErrTableNotAllowed is returned when a database rule names a table missing
+	// from Config.Tables.
+	ErrTableNotAllowed = errors.New("validate: table not allowed")
+	// ErrNoDialect is returned by the database rules when the SQL dialect of the
+	// database is unknown, see Config.Driver and Config.Dialect.
+	ErrNoDialect = errors.New("validate: no dialect")
+	// ErrPanic is returned, along with the path and the panic value, when a rule
+	// panics during a validation.
+	ErrPanic = errors.New("validate: rule panicked")
+)
+
+// FieldError describes a single failed rule.
+type FieldError struct {
+	// Path is the dotted JSON path of the value, e.g. "contacts.1.email".
+	Path string `json:"path"`
+	// Field is the key of the field or map entry that declared the rule.
+	Field string `json:"field"`
+	// Rule is the rule name without its parameters, e.g. "min".
+	Rule string `json:"rule"`
+	// Params holds the rule parameters, e.g. ["3"] for "min:3".
+	Params []string `json:"params,omitempty"`
+	// Message is the rendered (or custom) error message.
+	Message string `json:"message"`
+	// Locale is the locale the message was rendered in.
+	Locale string `json:"locale"`
+}
+
+// Error implements the error interface.
+func (e FieldError) Error() string {
+	return e.Message
+}
+
+// ValidationErrors is the error returned when one or more rules fail.
+// Entries are ordered by path.
+type ValidationErrors []FieldError
+
+// Error implements the error interface.
+func (e ValidationErrors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, fe := range e {
+		msgs = append(msgs, fe.Path+": "+fe.Message)
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Flatten returns the first message of every path, keyed by path.
+func (e ValidationErrors) Flatten() map[string]string {
+	if len(e) == 0 {
+		return nil
+	}
+	flat := make(map[string]string, len(e))
+	for _, fe := range e {
+		if _, ok := flat[fe.Path]; !ok {
+			flat[fe.Path] = fe.Message
+		}
+	}
+	return flat
+}
+
+// FlattenAll returns every message of every path, keyed by path.
+func (e ValidationErrors) FlattenAll() map[string][]string {
+	if len(e) == 0 {
+		return nil
+	}
+	flat := make(map[string][]string, len(e))
+	for _, fe := range e {
+		flat[fe.Path] = append(flat[fe.Path], fe.Message)
+	}
+	return flat
+}
+
+// ByField returns the errors reported for path and everything nested below it.
+func (e ValidationErrors) ByField(path string) ValidationErrors {
+	var res ValidationErrors
+	for _, fe := range e {
+		if fe.Path == path || strings.HasPrefix(fe.Path, path+".") {
+			res = append(res, fe)
+		}
+	}
+	return res
+}
+
+// Map converts the errors to the nested map returned by previous versions:
+// field keys map to a message, nested structs to a map and slices to a list
+// holding the failing elements. When a field fails along with its elements,
+// e.g. with AllErrors, the field keeps its message and the errors of its
+// elements are keyed by their path next to it, e.g. "items" and "items.0".
+func (e ValidationErrors) Map() map[string]any {
+	if len(e) == 0 {
+		return nil
+	}
+	items := make([]pathMessage, 0, len(e))
+	for _, fe := range e {
+		items = append(items, pathMessage{segs: strings.Split(fe.Path, "."), msg: fe.Message})
+	}
+	return nestMap(items)
+}
+
+// MapAll is like Map but lists every message of a field, e.g. when AllErrors is set.
+func (e ValidationErrors) MapAll() map[string]any {
+	if len(e) == 0 {
+		return nil
+	}
+	flat := e.FlattenAll()
+	items := make([]pathMessage, 0, len(flat))
+	for _, fe := range e {
+		if msgs, ok := flat[fe.Path]; ok {
+			items = append(items, pathMessage{segs: strings.Split(fe.Path, "."), msg: msgs})
+			delete(flat, fe.Path)
+		}
+	}
+	return nestMap(items)
+}
+
+// prefixed returns a copy of e with prefix prepended to every path.
+func (e ValidationErrors) prefixed(prefix string) ValidationErrors {
+	res := make(ValidationErrors, 0, len(e))
+	for _, fe := range e {
+		fe.Path = prefix + "." + fe.Path
+		res = append(res, fe)
+	}
+	return res
+}
+
+// sort orders e by path, comparing numeric segments as numbers so that
+// "items.2" comes before "items.10". Errors on the same path keep their order.
+func (e ValidationErrors) sort() {
+	slices.SortStableFunc(e, func(a, b FieldError) int {
+		return comparePaths(a.Path, b.Path)
+	})
+}
+
+type pathMessage struct {
+	segs []string
+	msg  any
+}
+
+func nestMap(items []pathMessage) map[string]any {
+	keys := make([]string, 0, len(items))
+	groups := make(map[string][]pathMessage)
+	for _, item := range items {
+		key := item.segs[0]
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], pathMessage{segs: item.segs[1:], msg: item.msg})
+	}
+	m := make(map[string]any, len(keys))
+	for _, key := range keys {
+		group := groups[key]
+		own := slices.IndexFunc(group, func(item pathMessage) bool { return len(item.segs) == 0 })
+		if own < 0 || len(group) == 1 {
+			m[key] = nestValue(group)
+			continue
+		}
+		// The message of the field cannot hold the errors of its elements
+		m[key] = group[own].msg
+		for _, item := range group {
+			if len(item.segs) > 0 {
+				m[key+"."+strings.Join(item.segs, ".")] = item.msg
+			}
+		}
+	}
+	return m
+}
+
+func nestValue(items []pathMessage) any {
+	indexed := true
+	for _, item := range items {
+		if len(item.segs) == 0 {
+			return item.msg
+		}
+		if _, err := strconv.Atoi(item.segs[0]); err != nil {
+			indexed = false
+		}
+	}
+	if !indexed {
+		return nestMap(items)
+	}
+	nested := nestMap(items)
+	indexes := make([]string, 0, len(nested))
+	for index := range nested {
+		indexes = append(indexes, index)
+	}
+	slices.SortFunc(indexes, comparePaths)
+	list := make([]any, 0, len(indexes))
+	for _, index := range indexes {
+		list = append(list, nested[index])
+	}
+	return list
+}
+
+func comparePaths(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		if as[i] == bs[i] {
+			continue
+		}
+		ai, aErr := strconv.Atoi(as[i])
+		bi, bErr := strconv.Atoi(bs[i])
+		if aErr == nil && bErr == nil {
+			return ai - bi
+		}
+		return strings.Compare(as[i], bs[i])
+	}
+	return len(as) - len(bs)
+}
@@ -2,51 +2,79 @@ package locale
 
 // EN locale validation message.
 var EN = map[string]any{
-	"required":        "The %s field is required.",
-	"string":          "The %s must be a string.",
-	"alpha":           "The %s may only contain letters.",
-	"numeric":         "The %s must be a number.",
-	"alpha_numeric":   "The %s may only contain letters and numbers.",
-	"int":             "The %s must be an integer.",
-	"uint":            "The %s must be a positive integer.",
-	"float":           "The %s must be a float.",
-	"email":           "The %s must be a valid email address.",
-	"phone":           "The %s must be a valid phone number.",
-	"phone_with_code": "The %s must be a valid phone number with country code.",
-	"username":        "The %s must be a valid email address or phone number or phone number with country code.",
-	"match":           "The %s does not matched.",
-	"same":            "The %s and %s must match.",
-	"unique":          "The %s has already been taken.",
-	"bool":            "The %s field must be true.",
-	"file":            "The %s must be a file.",
-	"file_type":       "The %s must be a file of type: %s.",
-	"image":           "The %s must be an image.",
-	"image_type":      "The %s must be an image of type: %s.",
-	"mimes":           "The %s must be a file of type: %s.",
-	"gh_card":         "The %s must be a valid Ghana Card.",
-	"gh_gps":          "The %s must be a valid Ghana digital address.",
-	"enum":            "The %s must be one of the following: %s.",
+	"required":         "The %s field is required.",
+	"required_if":      "The %s field is required when %s is %s.",
+	"required_unless":  "The %s field is required unless %s is in %s.",
+	"required_with":    "The %s field is required when %s is present.",
+	"required_without": "The %s field is required when %s is not present.",
+	"prohibited_if":    "The %s field is prohibited when %s is %s.",
+	"string":           "The %s must be a string.",
+	"alpha":            "The %s may only contain letters.",
+	"numeric":          "The %s must be a number.",
+	"alpha_numeric":    "The %s may only contain letters and numbers.",
+	"int":              "The %s must be an integer.",
+	"uint":             "The %s must be a positive integer.",
+	"float":            "The %s must be a float.",
+	"email":            "The %s must be a valid email address.",
+	"phone":            "The %s must be a valid phone number.",
+	"phone_with_code":  "The %s must be a valid phone number with country code.",
+	"username":         "The %s must be a valid email address or phone number or phone number with country code.",
+	"match":            "The %s does not matched.",
+	"same":             "The %s and %s must match.",
+	"unique":           "The %s has already been taken.",
+	"exists":           "The selected %s is invalid.",
+	"bool":             "The %s field must be true.",
+	"file":             "The %s must be a file.",
+	"file_type":        "The %s must be a file of type: %s.",
+	"image":            "The %s must be an image.",
+	"image_type":       "The %s must be an image of type: %s.",
+	"mimes":            "The %s must be a file of type: %s.",
+	"gh_card":          "The %s must be a valid Ghana Card.",
+	"gh_gps":           "The %s must be a valid Ghana digital address.",
+	"enum":             "The %s must be one of the following: %s.",
+	"invalid":          "The %s is invalid.",
+	"before":           "The %s must be a date before %s.",
+	"after":            "The %s must be a date after %s.",
+	"before_or_equal":  "The %s must be a date before or equal to %s.",
+	"after_or_equal":   "The %s must be a date after or equal to %s.",
+	"before_field":     "The %s must be a date before %s.",
+	"after_field":      "The %s must be a date after %s.",
+	"age_min":          "The %s must correspond to an age of at least %s years.",
+	"age_max":          "The %s must correspond to an age of at most %s years.",
+	"within":           "The %s must be within %s of the current date.",
 	"gt": map[string]string{
 		"numeric": "The %s must be greater than %s.",
 		"file":    "The %s must be greater than %s megabytes.",
+		"file_kb": "The %s must be greater than %s kilobytes.",
+		"file_mb": "The %s must be greater than %s megabytes.",
+		"file_gb": "The %s must be greater than %s gigabytes.",
 		"string":  "The %s must be greater than %s characters.",
 		"slice":   "The %s must have more than %s items.",
 	},
 	"gte": map[string]string{
 		"numeric": "The %s must be greater than or equal to %s.",
 		"file":    "The %s must be greater than or equal to %s megabytes.",
+		"file_kb": "The %s must be greater than or equal to %s kilobytes.",
+		"file_mb": "The %s must be greater than or equal to %s megabytes.",
+		"file_gb": "The %s must be greater than or equal to %s gigabytes.",
 		"string":  "The %s must be greater than or equal to %s characters.",
 		"slice":   "The %s must have %s items or more.",
 	},
 	"lt": map[string]string{
 		"numeric": "The %s must be less than %s.",
 		"file":    "The %s must be less than %s megabytes.",
+		"file_kb": "The %s must be less than %s kilobytes.",
+		"file_mb": "The %s must be less than %s megabytes.",
+		"file_gb": "The %s must be less than %s gigabytes.",
 		"string":  "The %s must be less than %s characters.",
 		"slice":   "The %s must have less than %s items.",
 	},
 	"lte": map[string]string{
 		"numeric": "The %s must be less than or equal to %s.",
 		"file":    "The %s must be less than or equal to %s megabytes.",
+		"file_kb": "The %s must be less than or equal to %s kilobytes.",
+		"file_mb": "The %s must be less than or equal to %s megabytes.",
+		"file_gb": "The %s must be less than or equal to %s gigabytes.",
 		"string":  "The %s must be less than or equal to %s characters.",
 		"slice":   "The %s must not have more than %s items.",
 	},
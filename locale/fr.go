@@ -2,51 +2,79 @@ package locale
 
 // FR locale validation message.
 var FR = map[string]any{
-	"required":        "Le champ %s est requis.",
-	"string":          "Le champ %s doit être une chaîne de caractères.",
-	"alpha":           "Le champ %s ne peut contenir que des lettres.",
-	"numeric":         "Le champ %s doit être un nombre.",
-	"alpha_numeric":   "Le champ %s ne peut contenir que des lettres et des chiffres.",
-	"int":             "Le champ %s doit être un entier.",
-	"uint":            "Le champ %s doit être un entier positif.",
-	"float":           "Le champ %s doit être un nombre décimal.",
-	"email":           "Le champ %s doit être une adresse e-mail valide.",
-	"phone":           "Le champ %s doit être un numéro de téléphone valide.",
-	"phone_with_code": "Le champ %s doit être un numéro de téléphone valide avec un code pays.",
-	"username":        "Le champ %s doit être une adresse e-mail, un numéro de téléphone ou un numéro de téléphone avec code pays valide.",
-	"match":           "Le champ %s ne correspond pas.",
-	"same":            "Les champs %s et %s doivent correspondre.",
-	"unique":          "Le champ %s a déjà été pris.",
-	"bool":            "Le champ %s doit être vrai.",
-	"file":            "Le champ %s doit être un fichier.",
-	"file_type":       "Le champ %s doit être un fichier du type : %s.",
-	"image":           "Le champ %s doit être une image.",
-	"image_type":      "Le champ %s doit être une image du type : %s.",
-	"mimes":           "Le champ %s doit être un fichier du type : %s.",
-	"gh_card":         "Le champ %s doit être une carte d'identité du Ghana valide.",
-	"gh_gps":          "Le champ %s doit être une adresse numérique du Ghana valide.",
-	"enum":            "Le champ %s n’est pas valide. Valeurs autorisées : %s.",
+	"required":         "Le champ %s est requis.",
+	"required_if":      "Le champ %s est requis lorsque %s vaut %s.",
+	"required_unless":  "Le champ %s est requis sauf si %s fait partie de %s.",
+	"required_with":    "Le champ %s est requis lorsque %s est présent.",
+	"required_without": "Le champ %s est requis lorsque %s n’est pas présent.",
+	"prohibited_if":    "Le champ %s est interdit lorsque %s vaut %s.",
+	"string":           "Le champ %s doit être une chaîne de caractères.",
+	"alpha":            "Le champ %s ne peut contenir que des lettres.",
+	"numeric":          "Le champ %s doit être un nombre.",
+	"alpha_numeric":    "Le champ %s ne peut contenir que des lettres et des chiffres.",
+	"int":              "Le champ %s doit être un entier.",
+	"uint":             "Le champ %s doit être un entier positif.",
+	"float":            "Le champ %s doit être un nombre décimal.",
+	"email":            "Le champ %s doit être une adresse e-mail valide.",
+	"phone":            "Le champ %s doit être un numéro de téléphone valide.",
+	"phone_with_code":  "Le champ %s doit être un numéro de téléphone valide avec un code pays.",
+	"username":         "Le champ %s doit être une adresse e-mail, un numéro de téléphone ou un numéro de téléphone avec code pays valide.",
+	"match":            "Le champ %s ne correspond pas.",
+	"same":             "Les champs %s et %s doivent correspondre.",
+	"unique":           "Le champ %s a déjà été pris.",
+	"exists":           "Le champ %s sélectionné est invalide.",
+	"bool":             "Le champ %s doit être vrai.",
+	"file":             "Le champ %s doit être un fichier.",
+	"file_type":        "Le champ %s doit être un fichier du type : %s.",
+	"image":            "Le champ %s doit être une image.",
+	"image_type":       "Le champ %s doit être une image du type : %s.",
+	"mimes":            "Le champ %s doit être un fichier du type : %s.",
+	"gh_card":          "Le champ %s doit être une carte d'identité du Ghana valide.",
+	"gh_gps":           "Le champ %s doit être une adresse numérique du Ghana valide.",
+	"enum":             "Le champ %s n’est pas valide. Valeurs autorisées : %s.",
+	"invalid":          "Le champ %s n’est pas valide.",
+	"before":           "Le champ %s doit être une date antérieure à %s.",
+	"after":            "Le champ %s doit être une date postérieure à %s.",
+	"before_or_equal":  "Le champ %s doit être une date antérieure ou égale à %s.",
+	"after_or_equal":   "Le champ %s doit être une date postérieure ou égale à %s.",
+	"before_field":     "Le champ %s doit être une date antérieure à %s.",
+	"after_field":      "Le champ %s doit être une date postérieure à %s.",
+	"age_min":          "Le champ %s doit correspondre à un âge d’au moins %s ans.",
+	"age_max":          "Le champ %s doit correspondre à un âge d’au plus %s ans.",
+	"within":           "Le champ %s doit être à moins de %s de la date actuelle.",
 	"gt": map[string]string{
 		"numeric": "Le champ %s doit être supérieur à %s.",
 		"file":    "Le champ %s doit être supérieur à %s mégaoctets.",
+		"file_kb": "Le champ %s doit être supérieur à %s kilooctets.",
+		"file_mb": "Le champ %s doit être supérieur à %s mégaoctets.",
+		"file_gb": "Le champ %s doit être supérieur à %s gigaoctets.",
 		"string":  "Le champ %s doit comporter plus de %s caractères.",
 		"slice":   "Le champ %s doit contenir plus de %s éléments.",
 	},
 	"gte": map[string]string{
 		"numeric": "Le champ %s doit être supérieur ou égal à %s.",
 		"file":    "Le champ %s doit être supérieur ou égal à %s mégaoctets.",
+		"file_kb": "Le champ %s doit être supérieur ou égal à %s kilooctets.",
+		"file_mb": "Le champ %s doit être supérieur ou égal à %s mégaoctets.",
+		"file_gb": "Le champ %s doit être supérieur ou égal à %s gigaoctets.",
 		"string":  "Le champ %s doit être supérieur ou égal à %s caractères.",
 		"slice":   "Le champ %s doit contenir %s éléments ou plus.",
 	},
 	"lt": map[string]string{
 		"numeric": "Le champ %s doit être inférieur à %s.",
 		"file":    "Le champ %s doit être inférieur à %s mégaoctets.",
+		"file_kb": "Le champ %s doit être inférieur à %s kilooctets.",
+		"file_mb": "Le champ %s doit être inférieur à %s mégaoctets.",
+		"file_gb": "Le champ %s doit être inférieur à %s gigaoctets.",
 		"string":  "Le champ %s doit comporter moins de %s caractères.",
 		"slice":   "Le champ %s doit contenir moins de %s éléments.",
 	},
 	"lte": map[string]string{
 		"numeric": "Le champ %s doit être inférieur ou égal à %s.",
 		"file":    "Le champ %s doit être inférieur ou égal à %s mégaoctets.",
+		"file_kb": "Le champ %s doit être inférieur ou égal à %s kilooctets.",
+		"file_mb": "Le champ %s doit être inférieur ou égal à %s mégaoctets.",
+		"file_gb": "Le champ %s doit être inférieur ou égal à %s gigaoctets.",
 		"string":  "Le champ %s doit être inférieur ou égal à %s caractères.",
 		"slice":   "Le champ %s ne doit pas contenir plus de %s éléments.",
 	},
@@ -0,0 +1,198 @@
+// Package locale holds the validation messages of every supported locale.
+//
+// Messages are format strings whose first %s is the field name and the
+// following ones the rule parameters. Rules with kind specific messages,
+// such as min, use a nested map keyed by kind ("min.string").
+package locale
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Default is the locale every fallback chain ends with.
+const Default = "en"
+
+var (
+	mu      sync.RWMutex
+	builtin = map[string]map[string]any{
+		"en": EN,
+		"fr": FR,
+	}
+	registry = maps.Clone(builtin)
+	labels   = map[string]map[string]string{}
+)
+
+// Register adds or replaces the messages of the locale tag, e.g. "tw" or "fr-CI".
+// Messages may be partial: missing keys are looked up along the fallback chain.
+func Register(tag string, messages map[string]any) {
+	mu.Lock()
+	defer mu.Unlock()
+	registry[Normalize(tag)] = messages
+}
+
+// Unregister removes the messages registered for the locale tag, e.g. by a test.
+// The built-in locales get their original messages back.
+func Unregister(tag string) {
+	mu.Lock()
+	defer mu.Unlock()
+	tag = Normalize(tag)
+	if messages, ok := builtin[tag]; ok {
+		registry[tag] = messages
+		return
+	}
+	delete(registry, tag)
+}
+
+// RegisterLabels adds or replaces field labels of the locale tag, keyed by field name.
+func RegisterLabels(tag string, fieldLabels map[string]string) {
+	mu.Lock()
+	defer mu.Unlock()
+	tag = Normalize(tag)
+	if labels[tag] == nil {
+		labels[tag] = make(map[string]string, len(fieldLabels))
+	}
+	for field, label := range fieldLabels {
+		labels[tag][field] = label
+	}
+}
+
+// UnregisterLabels removes the field labels of the locale tag.
+func UnregisterLabels(tag string) {
+	mu.Lock()
+	defer mu.Unlock()
+	delete(labels, Normalize(tag))
+}
+
+// Label returns the label of field for tag, walking the fallback chain.
+func Label(tag, field string) (string, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	for _, t := range Fallbacks(tag) {
+		if label, ok := labels[t][field]; ok {
+			return label, true
+		}
+	}
+	return "", false
+}
+
+// Lookup returns the messages registered for tag.
+func Lookup(tag string) (map[string]any, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	messages, ok := registry[Normalize(tag)]
+	return messages, ok
+}
+
+// Tags returns the registered locale tags, sorted.
+func Tags() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	tags := make([]string, 0, len(registry))
+	for tag := range registry {
+		tags = append(tags, tag)
+	}
+	slices.Sort(tags)
+	return tags
+}
+
+// Fallbacks returns the lookup chain of tag, most specific first.
+// For "fr-CI" it returns ["fr-ci", "fr", "en"].
+func Fallbacks(tag string) []string {
+	tag = Normalize(tag)
+	chain := make([]string, 0, 3)
+	for tag != "" {
+		chain = append(chain, tag)
+		i := strings.LastIndex(tag, "-")
+		if i < 0 {
+			break
+		}
+		tag = tag[:i]
+	}
+	if !slices.Contains(chain, Default) {
+		chain = append(chain, Default)
+	}
+	return chain
+}
+
+// Match returns the registered locale closest to tag: tag itself or one of its
+// parents, e.g. "fr" for "fr-CI" when only "fr" is registered. Unlike Fallbacks
+// it never falls back to Default, and reports false when nothing matches.
+func Match(tag string) (string, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	for tag = Normalize(tag); tag != ""; {
+		if _, ok := registry[tag]; ok {
+			return tag, true
+		}
+		i := strings.LastIndex(tag, "-")
+		if i < 0 {
+			break
+		}
+		tag = tag[:i]
+	}
+	return "", false
+}
+
+// Message returns the message of key for tag, walking the fallback chain.
+// Nested keys are separated by a dot, e.g. "min.string".
+func Message(tag, key string) (string, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	for _, t := range Fallbacks(tag) {
+		if msg, ok := lookup(registry[t], key); ok {
+			return msg, true
+		}
+	}
+	return "", false
+}
+
+// Keys returns the message keys of messages, nested keys joined by a dot, sorted.
+func Keys(messages map[string]any) []string {
+	keys := make([]string, 0, len(messages))
+	for key, value := range messages {
+		switch nested := value.(type) {
+		case map[string]string:
+			for kind := range nested {
+				keys = append(keys, key+"."+kind)
+			}
+		case map[string]any:
+			for _, kind := range Keys(nested) {
+				keys = append(keys, key+"."+kind)
+			}
+		default:
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+func lookup(messages map[string]any, key string) (string, bool) {
+	if messages == nil {
+		return "", false
+	}
+	if msg, ok := messages[key].(string); ok {
+		return msg, true
+	}
+	key, kind, ok := strings.Cut(key, ".")
+	if !ok {
+		return "", false
+	}
+	switch nested := messages[key].(type) {
+	case map[string]string:
+		msg, ok := nested[kind]
+		return msg, ok
+	case map[string]any:
+		return lookup(nested, kind)
+	}
+	return "", false
+}
+
+// Normalize returns the canonical form of tag used by the registry: lower case with
+// dashes, e.g. "fr-ci" for "fr_CI".
+func Normalize(tag string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(tag), "_", "-"))
+}
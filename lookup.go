@@ -0,0 +1,261 @@
+package valid
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Lookup answers the unique and exists rules. SQLLookup queries a SQL database,
+// MemoryLookup holds rows in memory and other implementations may query a cache
+// or a remote service.
+type Lookup interface {
+	// Exists reports whether resource, e.g. a table, holds an entry whose field
+	// equals value and which matches every condition.
+	Exists(ctx context.Context, resource, field string, value any, conditions ...Condition) (bool, error)
+}
+
+// BatchLookup is a Lookup checking many values at once, used with Config.BatchLookups.
+type BatchLookup interface {
+	Lookup
+	// ExistsAll is like Exists for each of values, e.g. in one query, and
+	// returns whether each value was found in the order of values.
+	ExistsAll(ctx context.Context, resource, field string, values []any, conditions ...Condition) ([]bool, error)
+}
+
+// Op is the operator of a Condition.
+type Op string
+
+const (
+	// OpEqual matches entries whose field equals the value.
+	OpEqual Op = "="
+	// OpNotEqual matches entries whose field differs from the value, e.g. to
+	// ignore the entry being updated.
+	OpNotEqual Op = "<>"
+	// OpEqualFold matches entries whose field equals the value case-insensitively.
+	// On the field of the lookup it makes the match of the looked up values
+	// case-insensitive and its own value is ignored.
+	OpEqualFold Op = "~="
+	// OpNull matches entries whose field is null or missing.
+	OpNull Op = "IS NULL"
+	// OpNotNull matches entries whose field is set.
+	OpNotNull Op = "IS NOT NULL"
+)
+
+// Condition is an extra condition of a lookup, e.g. the scope of a unique rule.
+type Condition struct {
+	Field string
+	Op    Op
+	Value any
+}
+
+// lookupConditions returns the conditions of a lookup of value in field,
+// starting with the match of the value itself.
+func lookupConditions(field string, value any, conditions []Condition) []Condition {
+	fold, conditions := splitFold(field, conditions)
+	match := Condition{Field: field, Op: OpEqual, Value: value}
+	if fold {
+		match.Op = OpEqualFold
+	}
+	return append([]Condition{match}, conditions...)
+}
+
+// splitFold reports whether conditions hold an OpEqualFold condition on field
+// and returns the other conditions.
+func splitFold(field string, conditions []Condition) (bool, []Condition) {
+	fold := false
+	others := make([]Condition, 0, len(conditions))
+	for _, c := range conditions {
+		if c.Field == field && c.Op == OpEqualFold {
+			fold = true
+		} else {
+			others = append(others, c)
+		}
+	}
+	return fold, others
+}
+
+// lookupQuery is the lookup of a database rule.
+type lookupQuery struct {
+	resource, field string
+	value           any
+	conditions      []Condition
+}
+
+// group identifies the lookups a BatchLookup answers together, i.e. those
+// differing only by their value.
+func (q lookupQuery) group() string {
+	return fmt.Sprintf("%s\x00%s\x00%v", q.resource, q.field, q.conditions)
+}
+
+func (q lookupQuery) key() string {
+	return fmt.Sprintf("%s\x00%T:%v", q.group(), q.value, q.value)
+}
+
+// lookupCache memoizes the lookups of one validation call, e.g. the unique rules
+// of slice elements sharing a value, and the results of batched lookups.
+type lookupCache struct {
+	mu      sync.Mutex
+	entries map[string]*cachedLookup
+}
+
+type cachedLookup struct {
+	once  sync.Once
+	found bool
+	err   error
+}
+
+func newLookupCache() *lookupCache {
+	return &lookupCache{entries: map[string]*cachedLookup{}}
+}
+
+func (c *lookupCache) entry(q lookupQuery) *cachedLookup {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	key := q.key()
+	e, ok := c.entries[key]
+	if !ok {
+		e = new(cachedLookup)
+		c.entries[key] = e
+	}
+	return e
+}
+
+// exists runs the lookup q with l once, concurrent callers wait for its result.
+func (c *lookupCache) exists(ctx context.Context, l Lookup, q lookupQuery) (bool, error) {
+	e := c.entry(q)
+	e.once.Do(func() {
+		e.found, e.err = l.Exists(ctx, q.resource, q.field, q.value, q.conditions...)
+	})
+	return e.found, e.err
+}
+
+// lookupBatch collects the lookups of a validation call for BatchLookup.ExistsAll.
+type lookupBatch struct {
+	mu     sync.Mutex
+	keys   map[string]bool
+	groups map[string][]lookupQuery
+}
+
+func (b *lookupBatch) add(q lookupQuery) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.keys == nil {
+		b.keys, b.groups = map[string]bool{}, map[string][]lookupQuery{}
+	}
+	if key := q.key(); !b.keys[key] {
+		b.keys[key] = true
+		b.groups[q.group()] = append(b.groups[q.group()], q)
+	}
+}
+
+// run looks up the groups of more than one value with l, in the order of their
+// keys, and caches the results. Single lookups are left to the validation.
+func (b *lookupBatch) run(ctx context.Context, l BatchLookup, cache *lookupCache) error {
+	for _, group := range slices.Sorted(maps.Keys(b.groups)) {
+		queries := b.groups[group]
+		if len(queries) < 2 {
+			continue
+		}
+		values := make([]any, len(queries))
+		for i, q := range queries {
+			values[i] = q.value
+		}
+		first := queries[0]
+		found, err := l.ExistsAll(ctx, first.resource, first.field, values, first.conditions...)
+		if err != nil {
+			return fmt.Errorf("validate: lookup %s.%s: %w", first.resource, first.field, err)
+		}
+		for i, q := range queries {
+			e := cache.entry(q)
+			e.once.Do(func() { e.found = found[i] })
+		}
+	}
+	return nil
+}
+
+// MemoryLookup is an in-memory Lookup, e.g. for tests. Resources hold rows
+// mapping fields to values. Values are compared by their string form, so that
+// 1 matches int64(1) and "1". It is safe for concurrent use.
+type MemoryLookup struct {
+	mu   sync.RWMutex
+	rows map[string][]map[string]any
+}
+
+// NewMemoryLookup returns a MemoryLookup holding rows, keyed by resource.
+func NewMemoryLookup(rows map[string][]map[string]any) *MemoryLookup {
+	l := &MemoryLookup{rows: make(map[string][]map[string]any, len(rows))}
+	for resource, resourceRows := range rows {
+		l.Add(resource, resourceRows...)
+	}
+	return l
+}
+
+// Add appends rows to resource.
+func (l *MemoryLookup) Add(resource string, rows ...map[string]any) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.rows[resource] = append(l.rows[resource], rows...)
+}
+
+// Exists implements Lookup.
+func (l *MemoryLookup) Exists(_ context.Context, resource, field string, value any, conditions ...Condition) (bool, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.exists(resource, lookupConditions(field, value, conditions)), nil
+}
+
+// ExistsAll implements BatchLookup.
+func (l *MemoryLookup) ExistsAll(_ context.Context, resource, field string, values []any, conditions ...Condition) ([]bool, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	found := make([]bool, len(values))
+	for i, value := range values {
+		found[i] = l.exists(resource, lookupConditions(field, value, conditions))
+	}
+	return found, nil
+}
+
+func (l *MemoryLookup) exists(resource string, conditions []Condition) bool {
+	for _, row := range l.rows[resource] {
+		if matchesRow(row, conditions) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesRow(row map[string]any, conditions []Condition) bool {
+	for _, c := range conditions {
+		val, ok := row[c.Field]
+		set := ok && val != nil
+		switch c.Op {
+		case OpEqual:
+			if !set || fmt.Sprint(val) != fmt.Sprint(c.Value) {
+				return false
+			}
+		case OpNotEqual:
+			if set && fmt.Sprint(val) == fmt.Sprint(c.Value) {
+				return false
+			}
+		case OpEqualFold:
+			if !set || !strings.EqualFold(fmt.Sprint(val), fmt.Sprint(c.Value)) {
+				return false
+			}
+		case OpNull:
+			if set {
+				return false
+			}
+		case OpNotNull:
+			if !set {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
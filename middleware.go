@@ -0,0 +1,198 @@
+package valid
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+// ErrUnsupportedContentType is wrapped by the DecodeError returned for request
+// content types that cannot be decoded.
+var ErrUnsupportedContentType = errors.New("validate: content-type not supported")
+
+// defaultValidator is used by Bind and Middleware when no Validator is given.
+var defaultValidator = New()
+
+type (
+	// MiddlewareConfig is configuration struct for Middleware.
+	MiddlewareConfig struct {
+		// ErrorHandler writes the response when decoding or validation fails.
+		// err is a *DecodeError, ValidationErrors or any other error returned by the Validator.
+		// It defaults to the JSON responses written by ValidateRequest, which hide the
+		// details of other errors, e.g. failed database queries, behind a 500.
+		ErrorHandler func(w http.ResponseWriter, r *http.Request, err error)
+		// FlatErrors writes validation errors keyed by path, e.g. "contacts.1.email",
+		// instead of the nested shape. It overrides Config.FlatErrors.
+		FlatErrors bool
+	}
+	// DecodeError is returned when the request body cannot be decoded.
+	DecodeError struct {
+		msg string
+		Err error
+	}
+	errorResponse struct {
+		Status bool `json:"status"`
+		Errors any  `json:"errors"`
+	}
+)
+
+// Error implements the error interface.
+func (e *DecodeError) Error() string {
+	return e.msg
+}
+
+// Unwrap returns the underlying decoding error.
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// Middleware decodes and validates the request body into a new T for every request.
+// The validated value is available to the next handler through FromContext[T].
+// Unlike RequestStruct it does not mutate v, so v can be shared between routes.
+func Middleware[T any](v Validator, config ...*MiddlewareConfig) func(http.Handler) http.Handler {
+	elemType := reflect.TypeFor[T]()
+	var cfg *MiddlewareConfig
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+	return func(next http.Handler) http.Handler {
+		return validateHandler(v, elemType, next, cfg)
+	}
+}
+
+// Bind decodes the request body into a new T and validates it.
+// It uses v when given and a default Validator otherwise.
+// The returned error is a *DecodeError, ValidationErrors or an argument error.
+func Bind[T any](r *http.Request, v ...Validator) (T, error) {
+	var elem T
+	var val Validator
+	if len(v) > 0 {
+		val = v[0]
+	}
+	err := bind(r, &elem, val)
+	return elem, err
+}
+
+func bind(r *http.Request, elem any, v Validator) error {
+	if v == nil {
+		v = defaultValidator
+	}
+	if val, ok := v.(*validation); ok {
+		v = val.forRequest(r)
+	}
+	if err := decodeRequest(r, elem); err != nil {
+		return err
+	}
+	return v.ValidateStructCtx(r.Context(), elem)
+}
+
+func validateHandler(v Validator, elemType reflect.Type, next http.Handler, config *MiddlewareConfig) http.Handler {
+	flat, all := false, false
+	if val, ok := v.(*validation); ok {
+		flat, all = val.flatErrors, val.allErrors
+	}
+	if config != nil && config.FlatErrors {
+		flat = true
+	}
+	handleError := errorWriter(flat, all)
+	if config != nil && config.ErrorHandler != nil {
+		handleError = config.ErrorHandler
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Ensure body is closed
+		defer func() {
+			if r.Body != nil {
+				_ = r.Body.Close()
+			}
+		}()
+
+		if elemType == nil {
+			writeJSONError(w, http.StatusInternalServerError, "validation struct type not initialized")
+			return
+		}
+
+		// Create a new instance of the struct for this request to avoid race conditions
+		reqElem := reflect.New(elemType).Interface()
+		if err := bind(r, reqElem, v); err != nil {
+			handleError(w, r, err)
+			return
+		}
+
+		// Pass the validated struct to the next handler, see FromContext
+		next.ServeHTTP(w, r.WithContext(newContext(r.Context(), reqElem)))
+	})
+}
+
+// decodeRequest decodes the request body into elem according to its content type.
+func decodeRequest(r *http.Request, elem any) error {
+	contentType := r.Header.Get("Content-Type")
+	if strings.HasPrefix(contentType, "multipart/form-data") || strings.HasPrefix(contentType, "application/x-www-form-urlencoded") {
+		if err := decodeMultipart(r, elem); err != nil {
+			return &DecodeError{msg: fmt.Sprintf("failed to decode form: %s", err.Error()), Err: err}
+		}
+	} else if strings.HasPrefix(contentType, "application/json") {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			return &DecodeError{msg: fmt.Sprintf("failed to read body: %s", err.Error()), Err: err}
+		}
+		if err := json.Unmarshal(body, elem); err != nil {
+			return &DecodeError{msg: fmt.Sprintf("failed to unmarshal: %s", err.Error()), Err: err}
+		}
+	} else if strings.HasPrefix(contentType, "text/xml") || strings.HasPrefix(contentType, "application/xml") {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			return &DecodeError{msg: fmt.Sprintf("failed to read body: %s", err.Error()), Err: err}
+		}
+		if err := xml.Unmarshal(body, elem); err != nil {
+			return &DecodeError{msg: fmt.Sprintf("failed to unmarshal: %s", err.Error()), Err: err}
+		}
+	} else {
+		return &DecodeError{msg: fmt.Sprintf("content-type: %s, not supported.", contentType), Err: ErrUnsupportedContentType}
+	}
+	return nil
+}
+
+// errInternal is the message of the responses to errors other than decoding and
+// validation errors.
+const errInternal = "validate: internal error"
+
+// errorWriter returns the default JSON response writer for errors returned by bind.
+// Validation errors are keyed by path when flat is set and nested otherwise, with
+// the list of messages of each field when all is set.
+func errorWriter(flat, all bool) func(w http.ResponseWriter, r *http.Request, err error) {
+	return func(w http.ResponseWriter, _ *http.Request, err error) {
+		var decodeErr *DecodeError
+		var errs ValidationErrors
+		switch {
+		case errors.As(err, &decodeErr):
+			writeJSONError(w, http.StatusBadRequest, decodeErr.Error())
+		case errors.As(err, &errs):
+			var body any
+			switch {
+			case flat && all:
+				body = errs.FlattenAll()
+			case flat:
+				body = errs.Flatten()
+			case all:
+				body = errs.MapAll()
+			default:
+				body = errs.Map()
+			}
+			resByte, _ := json.Marshal(errorResponse{Status: false, Errors: body})
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			_, _ = w.Write(resByte)
+		case errors.Is(err, ErrCanceled):
+			writeJSONError(w, http.StatusServiceUnavailable, err.Error())
+		default:
+			// Database errors and panics may reveal internals such as the schema,
+			// ErrorHandler receives them as is
+			writeJSONError(w, http.StatusInternalServerError, errInternal)
+		}
+	}
+}
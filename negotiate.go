@@ -0,0 +1,86 @@
+package valid
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/seyramlabs/valid/locale"
+)
+
+// forRequest returns the validator to use for r: v itself, or a copy rendering
+// messages in the locale negotiated from the request when enabled in Config.
+func (v *validation) forRequest(r *http.Request) *validation {
+	if !v.acceptLanguage && v.localeParam == "" {
+		return v
+	}
+	tag, ok := v.negotiateLocale(r)
+	if !ok {
+		return v
+	}
+	val := *v
+	val.locale = tag
+	return &val
+}
+
+// negotiateLocale picks a registered locale from the LocaleParam query parameter
+// or, failing that, from the Accept-Language header.
+func (v *validation) negotiateLocale(r *http.Request) (string, bool) {
+	if v.localeParam != "" {
+		if tag, ok := locale.Match(r.URL.Query().Get(v.localeParam)); ok {
+			return tag, true
+		}
+	}
+	if v.acceptLanguage {
+		for _, tag := range parseAcceptLanguage(r.Header.Get("Accept-Language")) {
+			if tag, ok := locale.Match(tag); ok {
+				return tag, true
+			}
+		}
+	}
+	return "", false
+}
+
+// parseAcceptLanguage returns the language tags of an Accept-Language header
+// ordered by decreasing quality. Wildcards and tags with q=0 are dropped.
+func parseAcceptLanguage(header string) []string {
+	type language struct {
+		tag string
+		q   float64
+	}
+	languages := make([]language, 0, 4)
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		tag = strings.TrimSpace(tag)
+		if tag == "" || tag == "*" {
+			continue
+		}
+		q := 1.0
+		for _, param := range strings.Split(params, ";") {
+			if value, ok := strings.CutPrefix(strings.TrimSpace(param), "q="); ok {
+				if f, err := strconv.ParseFloat(value, 64); err == nil {
+					q = f
+				}
+			}
+		}
+		if q <= 0 {
+			continue
+		}
+		languages = append(languages, language{tag: tag, q: q})
+	}
+	slices.SortStableFunc(languages, func(a, b language) int {
+		switch {
+		case a.q > b.q:
+			return -1
+		case a.q < b.q:
+			return 1
+		}
+		return 0
+	})
+	tags := make([]string, 0, len(languages))
+	for _, l := range languages {
+		tags = append(tags, l.tag)
+	}
+	return tags
+}
@@ -1,8 +1,7 @@
 package valid
 
 import (
-	"database/sql"
-	"errors"
+	"cmp"
 	"fmt"
 	"mime/multipart"
 	"net/mail"
@@ -18,6 +17,8 @@ import (
 
 func isEmpty(v reflect.Value) bool {
 	switch v.Kind() {
+	case reflect.Invalid:
+		return true
 	case reflect.String, reflect.Array:
 		return v.Len() == 0
 	case reflect.Map, reflect.Slice:
@@ -33,7 +34,7 @@ func isEmpty(v reflect.Value) bool {
 	case reflect.Interface, reflect.Ptr:
 		return v.IsNil()
 	}
-	return reflect.DeepEqual(v.Interface(), reflect.Zero(v.Type()).Interface())
+	return v.IsZero()
 }
 func isNotInt(v reflect.Value) bool {
 	rgx, _ := regexp.Compile(`^(?:[-]?(?:0|[1-9][0-9]*))$`)
@@ -188,7 +189,7 @@ func isNotBetween(v reflect.Value, min, max string) bool {
 	return false
 }
 func isNotEnum(v reflect.Value, eunms any) bool {
-	return !slices.Contains(eunms.([]string), v.Interface().(string))
+	return !slices.Contains(eunms.([]string), fmt.Sprint(v.Interface()))
 }
 func isNotFrom(v reflect.Value, min, max string) bool {
 	switch v.Kind() {
@@ -264,24 +265,6 @@ func isNotMimes(v reflect.Value, mimes string) bool {
 	}
 	return false
 }
-func isNotUnique(dbConfig *Database, value, field, table string) bool {
-	db := connectDB(dbConfig)
-	defer func() {
-		_ = db.Close()
-	}()
-	dbField := snakeCase(field)
-	queryStr := fmt.Sprintf("SELECT %s FROM %s WHERE %s=?", dbField, table, dbField)
-	if dbConfig.Driver == DriverPostgres {
-		queryStr = fmt.Sprintf("SELECT 1 FROM %s WHERE %s=$1", table, dbField)
-	}
-	if err := db.QueryRow(queryStr, value).Scan(&value); err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return false
-		}
-		panic(err)
-	}
-	return true
-}
 func isNotDatetime(v reflect.Value, kind string) bool {
 	switch kind {
 	case "rfc3339":
@@ -308,3 +291,116 @@ func isNotDatetime(v reflect.Value, kind string) bool {
 		panic(fmt.Sprintf("date format not supported: %s", kind))
 	}
 }
+func isNotCompared(v reflect.Value, op, comparable string) bool {
+	val, err := strconv.ParseFloat(comparable, 64)
+	if err != nil {
+		return true
+	}
+	num, ok := numberOf(v)
+	return !ok || failsComparison(op, cmp.Compare(num, val))
+}
+func isNotComparedTo(v1, v2 reflect.Value, op string) bool {
+	// Dates compare chronologically, other strings by length
+	if t1, ok := timeOf(v1); ok {
+		if t2, ok := timeOf(v2); ok {
+			return failsComparison(op, t1.Compare(t2))
+		}
+	}
+	n1, ok1 := numberOf(v1)
+	n2, ok2 := numberOf(v2)
+	return !ok1 || !ok2 || failsComparison(op, cmp.Compare(n1, n2))
+}
+func failsComparison(op string, c int) bool {
+	switch op {
+	case "gt":
+		return c <= 0
+	case "gte":
+		return c < 0
+	case "lt":
+		return c >= 0
+	case "lte":
+		return c > 0
+	}
+	return false
+}
+func numberOf(v reflect.Value) (float64, bool) {
+	switch v.Kind() {
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+		return float64(v.Len()), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	}
+	return 0, false
+}
+func parseDate(s string) (time.Time, bool) {
+	for _, layout := range []string{time.RFC3339, time.DateTime, time.DateOnly} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+func isAnyOf(v reflect.Value, values []string) bool {
+	if !v.IsValid() {
+		return false
+	}
+	if v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return false
+		}
+		v = v.Elem()
+	}
+	return slices.Contains(values, fmt.Sprint(v.Interface()))
+}
+func isNotDateCompared(op string, t, limit time.Time) bool {
+	switch op {
+	case "before":
+		return !t.Before(limit)
+	case "after":
+		return !t.After(limit)
+	case "before_or_equal":
+		return t.After(limit)
+	case "after_or_equal":
+		return t.Before(limit)
+	}
+	return false
+}
+func timeOf(v reflect.Value) (time.Time, bool) {
+	switch v.Kind() {
+	case reflect.String:
+		return parseDate(v.String())
+	case reflect.Struct:
+		t, ok := v.Interface().(time.Time)
+		return t, ok && !t.IsZero()
+	case reflect.Pointer, reflect.Interface:
+		if !v.IsNil() {
+			return timeOf(v.Elem())
+		}
+	}
+	return time.Time{}, false
+}
+func parseDateParam(param string, now time.Time) (time.Time, bool) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	switch param {
+	case "now":
+		return now, true
+	case "today":
+		return today, true
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), true
+	case "yesterday":
+		return today.AddDate(0, 0, -1), true
+	}
+	return parseDate(param)
+}
+func age(birth, now time.Time) int {
+	years := now.Year() - birth.Year()
+	if now.Month() < birth.Month() || (now.Month() == birth.Month() && now.Day() < birth.Day()) {
+		years--
+	}
+	return years
+}
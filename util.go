@@ -1,16 +1,13 @@
 package valid
 
 import (
-	"database/sql"
-	"fmt"
 	"io"
 	"mime/multipart"
+	"reflect"
+	"strconv"
 	"strings"
-
-	// import github.com/go-sql-driver/mysql
-	_ "github.com/go-sql-driver/mysql"
-	// import github.com/lib/pq
-	_ "github.com/lib/pq"
+	"time"
+	"unicode"
 )
 
 func getRuleAndMsg(r string) (rule, customMsg string) {
@@ -23,20 +20,181 @@ func getRuleAndMsg(r string) (rule, customMsg string) {
 	}
 	return
 }
+
+// ruleName returns the rule a message key belongs to, e.g. "min" for "min.string".
+func ruleName(ruleKey string) string {
+	switch ruleKey {
+	case "bool":
+		return "required"
+	case "image_type":
+		return "image"
+	case "file_type":
+		return "file"
+	}
+	if strings.HasPrefix(ruleKey, "date.") {
+		return strings.TrimPrefix(ruleKey, "date.")
+	}
+	name, _, _ := strings.Cut(ruleKey, ".")
+	return name
+}
+
+var timeType = reflect.TypeFor[time.Time]()
+
+// isNestedStruct reports whether t is a struct validated field by field, i.e. any struct but time.Time.
+func isNestedStruct(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && t != timeType
+}
+
+var fileHeaderType = reflect.TypeFor[*multipart.FileHeader]()
+
+// isNestedValue reports whether value is a struct, or a non nil pointer to one,
+// validated field by field. Dates and files are validated by their own rules.
+func isNestedValue(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.Struct:
+		return isNestedStruct(value.Type())
+	case reflect.Pointer:
+		return !value.IsNil() && value.Type() != fileHeaderType && isNestedStruct(value.Type().Elem())
+	}
+	return false
+}
+
+// typeRules maps the rules checking the type of a value to the kinds they accept.
+// Values of other kinds fail them, e.g. 42 for string in ValidateMap.
+var typeRules = map[string]func(reflect.Kind) bool{
+	"string":          isStringKind,
+	"ascii":           isStringKind,
+	"alpha":           isStringKind,
+	"alpha_numeric":   isStringKind,
+	"email":           isStringKind,
+	"phone":           isStringKind,
+	"phone_with_code": isStringKind,
+	"username":        isStringKind,
+	"gh_card":         isStringKind,
+	"gh_gps":          isStringKind,
+	"numeric":         func(k reflect.Kind) bool { return isStringKind(k) || isNumberKind(k) },
+	"int":             isNumberKind,
+	"uint":            isNumberKind,
+	"float":           isNumberKind,
+}
+
+// isScalarKind reports whether k is the kind of a single value checked by the
+// type rules, i.e. not a collection, a struct or a pointer.
+func isScalarKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Invalid, reflect.Slice, reflect.Array, reflect.Map, reflect.Struct, reflect.Pointer, reflect.Interface:
+		return false
+	}
+	return true
+}
+
+func isStringKind(k reflect.Kind) bool {
+	return k == reflect.String
+}
+
+// isNumberKind reports whether k is an integer or a float kind. JSON numbers
+// decoded into a map are float64, the int rules check they are whole.
+func isNumberKind(k reflect.Kind) bool {
+	return (k >= reflect.Int && k <= reflect.Uintptr) || k == reflect.Float32 || k == reflect.Float64
+}
+
+func isEachRule(ruleOrMsg string) bool {
+	return strings.HasPrefix(ruleOrMsg, "each:")
+}
+
+// fieldName returns the name of field in nameTag without the tag options such as
+// ",omitempty", or the Go name when the tag has none. It is empty for fields tagged "-".
+func fieldName(field reflect.StructField, nameTag string) string {
+	name, _, _ := strings.Cut(field.Tag.Get(nameTag), ",")
+	switch name {
+	case "-":
+		return ""
+	case "":
+		return field.Name
+	}
+	return name
+}
+func isComparison(op string) bool {
+	return op == "gt" || op == "gte" || op == "lt" || op == "lte"
+}
+
+// splitMapRules separates the rules of a map from the keys: and values: groups.
+// A group starts with its prefix and runs until the next group, e.g. in
+// "max:10|keys:alpha_numeric|max:32|values:max:256" the keys are limited to 32
+// characters and the map itself to 10 entries.
+func splitMapRules(ruleOrMsgs []string) (mapRules, keyRules, valueRules []string) {
+	group := &mapRules
+	for _, ruleOrMsg := range ruleOrMsgs {
+		if rule, ok := strings.CutPrefix(ruleOrMsg, "keys:"); ok {
+			group, ruleOrMsg = &keyRules, rule
+		} else if rule, ok := strings.CutPrefix(ruleOrMsg, "values:"); ok {
+			group, ruleOrMsg = &valueRules, rule
+		}
+		*group = append(*group, ruleOrMsg)
+	}
+	return
+}
+
+// formatFieldName turns a field name into lower case words, e.g. "user id" for
+// "userID", "http server" for "HTTPServer" and "first name" for "first_name".
 func formatFieldName(field string) string {
-	var text string
-	for i := 0; i < len(field); i++ {
-		c := string([]byte{field[i]})
-		if c == strings.ToUpper(c) {
-			if len(text) != 0 {
-				text += " "
+	runes := []rune(field)
+	var b strings.Builder
+	space := false
+	for i, r := range runes {
+		if r == '_' || r == '-' || r == '.' || unicode.IsSpace(r) {
+			space = b.Len() > 0
+			continue
+		}
+		if unicode.IsUpper(r) && i > 0 {
+			prev := runes[i-1]
+			// A capital starts a word after a lower case letter or a digit, or ends an acronym
+			endsAcronym := unicode.IsUpper(prev) && i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || endsAcronym {
+				space = true
 			}
-			text += strings.ToLower(c)
-		} else {
-			text += strings.ToLower(c)
 		}
+		if space {
+			b.WriteByte(' ')
+			space = false
+		}
+		b.WriteRune(unicode.ToLower(r))
 	}
-	return text
+	return b.String()
+}
+
+// parseFileSize parses sizes such as "2mb" into bytes. It also returns the number
+// and the message kind of the unit, e.g. "2" and "file_mb".
+func parseFileSize(s string) (limit int64, size, kind string, ok bool) {
+	matches := fileSizeRegex.FindStringSubmatch(s)
+	if matches == nil {
+		return 0, "", "", false
+	}
+	size, unit := matches[1], strings.ToLower(matches[2])
+	size64, _ := strconv.ParseInt(size, 10, 64)
+	switch unit {
+	case "kb":
+		limit = kilobyte * size64
+	case "mb":
+		limit = megabyte * size64
+	case "gb":
+		limit = gigabyte * size64
+	default:
+		return 0, "", "", false
+	}
+	return limit, size, "file_" + unit, true
+}
+
+// parseDuration parses durations such as "30d", "2w" or "12h".
+func parseDuration(s string) (time.Duration, bool) {
+	for suffix, unit := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if n, ok := strings.CutSuffix(s, suffix); ok {
+			count, err := strconv.Atoi(n)
+			return time.Duration(count) * unit, err == nil
+		}
+	}
+	d, err := time.ParseDuration(s)
+	return d, err == nil
 }
 func readFile(fh *multipart.FileHeader) ([]byte, error) {
 	file, err := fh.Open()
@@ -59,8 +217,8 @@ func snakeCase(camel string) (snake string) {
 	diff := 'a' - 'A'
 	l := len(camel)
 	for i, v := range camel {
-		// A is 65, a is 97
-		if v >= 'a' {
+		// A is 65, a is 97. Only upper case letters start a word, e.g. "deleted_at" is kept
+		if v < 'A' || v > 'Z' {
 			b.WriteRune(v)
 			continue
 		}
@@ -71,56 +229,3 @@ func snakeCase(camel string) (snake string) {
 	}
 	return b.String()
 }
-
-// Database configuration
-type Database struct {
-	Host     string
-	Port     int
-	Name     string
-	Username string
-	Password string
-	Driver   string
-	SSLMode  string
-}
-
-func connectDB(config *Database) *sql.DB {
-	switch config.Driver {
-	case DriverPostgres:
-		var SSLMode string
-		if config.SSLMode != "" {
-			SSLMode = config.SSLMode
-		} else {
-			SSLMode = "disable"
-		}
-		dsn := fmt.Sprintf(
-			"host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
-			config.Host,
-			config.Port,
-			config.Username,
-			config.Password,
-			config.Name,
-			SSLMode,
-		)
-		db, err := sql.Open(config.Driver, dsn)
-		if err != nil {
-			panic(fmt.Sprintf("[db connection error] %v", err))
-		}
-		return db
-	case DriverMysql:
-		dsn := fmt.Sprintf(
-			"%s:%s@tcp(%s:%d)/%s",
-			config.Username,
-			config.Password,
-			config.Host,
-			config.Port,
-			config.Name,
-		)
-		db, err := sql.Open(config.Driver, dsn)
-		if err != nil {
-			panic(fmt.Sprintf("[db connection error] %v", err))
-		}
-		return db
-	default:
-		panic(fmt.Sprintf("[db connection error]: provide db driver. validata currently support %s and %s drivers.", DriverMysql, DriverPostgres))
-	}
-}
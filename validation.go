@@ -4,17 +4,22 @@
 package valid
 
 import (
+	"cmp"
+	"context"
+	"database/sql"
 	"encoding/json"
-	"encoding/xml"
+	"errors"
 	"fmt"
-	"io"
+	"math"
 	"mime/multipart"
 	"net/http"
 	"reflect"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/seyramlabs/valid/locale"
 )
@@ -30,96 +35,338 @@ const (
 	DriverPostgres = "postgres"
 	// DriverMysql mysql driver for database connection
 	DriverMysql = "mysql"
+	// DriverSQLite sqlite3 driver for database connection
+	DriverSQLite = "sqlite3"
 )
 
 // Precompiled regex for file size validation to optimize performance
 var fileSizeRegex = regexp.MustCompile(`^([1-9]|[1-9][0-9]+)(kb|KB|mb|MB|gb|GB|tb|TB)$`)
 
-type (
-	message struct {
-		K string
-		V any
-	}
-	// Config is configuration struct for Validate.
-	Config struct {
-		Locale string
-		DB     *Database
-	}
-)
+// Config is configuration struct for Validate.
+type Config struct {
+	Locale string
+	// DB opens the connection pool used by database rules such as unique,
+	// unless Querier is set. The pool belongs to the Validator: create one
+	// Validator for the application, or call Close once done with it.
+	DB *Database
+	// Querier runs the queries of database rules, e.g. an existing *sql.DB.
+	// It is reused across validations and takes precedence over DB.
+	Querier Querier
+	// Driver is the database/sql driver of Querier, selecting its dialect with
+	// DialectFor, e.g. DriverPostgres. Querier needs it or Dialect, the database
+	// rules return ErrNoDialect otherwise.
+	Driver string
+	// Dialect is the SQL dialect of Querier or DB. It takes precedence over the
+	// dialect of the driver and is required by drivers without a built-in one.
+	Dialect Dialect
+	// Lookup answers the unique and exists rules instead of a SQL database,
+	// e.g. a MemoryLookup. It takes precedence over Querier and DB.
+	Lookup Lookup
+	// BatchLookups runs the lookups of the unique and exists rules sharing a table,
+	// column and options together when the Lookup is a BatchLookup, e.g. one
+	// query for the unique rule of every element of a slice, see
+	// SQLLookup.ExistsAll. Identical lookups of a validation run once in any case.
+	BatchLookups bool
+	// Tables lists the tables database rules may query, e.g. "users" or
+	// "public.users". Rules naming other tables return ErrTableNotAllowed.
+	// Every table is allowed when empty.
+	Tables []string
+	// AcceptLanguage negotiates the locale of each request from its Accept-Language
+	// header in ValidateRequest, Middleware and Bind, falling back to Locale.
+	AcceptLanguage bool
+	// LocaleParam is the query parameter selecting the locale of a request, e.g. "lang".
+	// It takes precedence over the Accept-Language header.
+	LocaleParam string
+	// Now is the clock used by date rules such as after:now or age_min:18.
+	// It defaults to time.Now.
+	Now func() time.Time
+	// NameTag is the struct tag naming fields in error paths and sibling
+	// references, e.g. "xml", "form", "yaml" or a custom "param" tag. The label
+	// tag is reserved for the names of fields in messages.
+	// It defaults to "json". Fields without the tag use their Go name and
+	// fields tagged "-" are skipped.
+	NameTag string
+	// FlatErrors makes ValidateRequest and Middleware respond with errors keyed
+	// by path, e.g. {"contacts.1.email": "..."}, instead of the nested shape.
+	// ValidationErrors.Flatten returns the same set.
+	FlatErrors bool
+	// AllErrors reports every failing rule of a field instead of the first one.
+	// Fields with the bail rule still stop at their first failure. The responses
+	// of ValidateRequest and Middleware then list the messages of each field.
+	AllErrors bool
+}
 
 type Validator interface {
 	// ValidateStruct performs validation on struct.
-	// It takes struct pointer as parameter.
-	ValidateStruct(elem any) map[string]any
+	// It takes struct pointer as parameter and returns ValidationErrors when rules fail.
+	ValidateStruct(elem any) error
+	// ValidateStructCtx is like ValidateStruct but passes ctx to the rules querying
+	// the database and to custom rules registered with RegisterRuleCtx.
+	// It returns an error wrapping ErrCanceled and ctx.Err() when ctx is done first.
+	ValidateStructCtx(ctx context.Context, elem any) error
 	// RequestStruct takes struct pointer as parameter.
 	RequestStruct(elem any) Validator
 	// ValidateRequest performs validation on in coming request.
 	// It is a middleware that takes http.Handler as parameter and return  http.Handler.
+	// The validated struct is available to next through FromContext.
 	ValidateRequest(next http.Handler) http.Handler
 	// ValidateMap performs validation on map.
-	// It takes map pointer as parameter.
-	ValidateMap(elem map[string]any, rule map[string]string, message ...map[string]string) map[string]any
+	// Rules are keyed by map key and use the validate tag syntax.
+	ValidateMap(elem map[string]any, rule map[string]string, message ...map[string]string) error
+	// RegisterRule registers a custom rule usable like the built-in ones.
+	// messages maps a locale to the message format.
+	RegisterRule(name string, fn RuleFunc, messages map[string]string) error
+	// RegisterRuleCtx is like RegisterRule for rules using the validation context.
+	RegisterRuleCtx(name string, fn RuleFuncCtx, messages map[string]string) error
+	// Close closes the connection pool opened for Config.DB. It does nothing when
+	// the database is given as Querier or Lookup, which their owner closes.
+	Close() error
 }
 
 type validation struct {
-	jsonRes struct {
-		Status bool `json:"status"`
-		Errors any  `json:"errors"`
-	}
+	ctx       context.Context
+	errs      *runErrors
+	lookups   *lookupCache
+	batch     *lookupBatch
 	elem      any
 	elemType  reflect.Type
 	elemValue reflect.Value
+	mapElem   map[string]any
+	messages  map[string]string
 	locale    string
-	dbConfig  *Database
+	lookup    Lookup
+	pool      *sql.DB
+	batching  bool
+	tables    []string
+	dbErr     error
+	rules     *ruleRegistry
+	now       func() time.Time
+	nameTag   string
+	allErrors bool
+
+	acceptLanguage bool
+	localeParam    string
+	flatErrors     bool
 }
 
 // New takes optional @Config object.
 // Valid use this configuration to connect to your database to check for exist field in validation.
 func New(config ...*Config) Validator {
 	instance := new(validation)
+	instance.rules = newRuleRegistry()
 	if len(config) > 0 && config[0] != nil {
 		instance.locale = config[0].Locale
-		instance.dbConfig = config[0].DB
+		instance.tables = config[0].Tables
+		instance.batching = config[0].BatchLookups
+		instance.lookup, instance.pool, instance.dbErr = newLookup(config[0])
+		instance.acceptLanguage = config[0].AcceptLanguage
+		instance.localeParam = config[0].LocaleParam
+		instance.now = config[0].Now
+		instance.nameTag = config[0].NameTag
+		instance.flatErrors = config[0].FlatErrors
+		instance.allErrors = config[0].AllErrors
+	}
+	if instance.now == nil {
+		instance.now = time.Now
 	}
 	if instance.locale == "" {
 		instance.locale = "en" // Default locale
 	}
+	if instance.nameTag == "" {
+		instance.nameTag = "json"
+	}
 	return instance
 }
 
+// RegisterLocale adds or replaces the messages of a locale, e.g. "tw" or "fr-CI".
+// Missing messages fall back to the parent locale and then to English, see locale.Fallbacks.
+func RegisterLocale(tag string, messages map[string]any) {
+	locale.Register(tag, messages)
+}
+
+// RegisterLabels adds or replaces field labels of a locale, keyed by field name,
+// e.g. RegisterLabels("fr", map[string]string{"dateOfBirth": "date de naissance"}).
+// Labels take precedence over the label tag of a field.
+func RegisterLabels(tag string, labels map[string]string) {
+	locale.RegisterLabels(tag, labels)
+}
+
 // ValidateStruct performs validation on struct.
-// It takes struct pointer as parameter.
-func (v *validation) ValidateStruct(elem any) map[string]any {
+// It takes struct pointer as parameter and returns ValidationErrors when rules fail.
+// ErrNotPointer and ErrNotStruct are returned for invalid arguments.
+func (v *validation) ValidateStruct(elem any) error {
+	return v.ValidateStructCtx(context.Background(), elem)
+}
+
+// ValidateStructCtx is like ValidateStruct but passes ctx to the rules, see Validator.
+func (v *validation) ValidateStructCtx(ctx context.Context, elem any) error {
 	elemType := reflect.TypeOf(elem)
 	elemValue := reflect.ValueOf(elem)
 
 	if elemType == nil || elemType.Kind() != reflect.Pointer || elemValue.Kind() != reflect.Pointer {
-		// Return error map instead of panicking for production safety
-		return map[string]any{"error": "validate: a pointer is expected as an argument"}
+		// Return an error instead of panicking for production safety
+		return ErrNotPointer
+	}
+	if elemType.Elem().Kind() != reflect.Struct || elemValue.IsNil() {
+		return ErrNotStruct
 	}
 
 	// Create a temporary validation context to avoid race conditions on v.elem
-	valCtx := &validation{
-		elem:      elem,
-		elemType:  elemType.Elem(),
-		elemValue: elemValue.Elem(),
+	valCtx := v.child()
+	valCtx.ctx = ctx
+	valCtx.errs = new(runErrors)
+	valCtx.elem = elem
+	valCtx.elemType = elemType.Elem()
+	valCtx.elemValue = elemValue.Elem()
+	valCtx.lookups = newLookupCache()
+
+	valCtx.batchLookups(valCtx.structValidator)
+	errs := valCtx.structValidator()
+	if err := ctx.Err(); err != nil {
+		// Rules skipped or failed because of the context, the errors are incomplete
+		return fmt.Errorf("%w: %w", ErrCanceled, err)
+	}
+	if err := valCtx.errs.get(); err != nil {
+		return err
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// ValidateMap performs validation on map.
+// Rules use the validate tag syntax and are keyed by map key. The optional
+// message map overrides messages per key ("name") or per rule ("name.required").
+func (v *validation) ValidateMap(elem map[string]any, rule map[string]string, message ...map[string]string) error {
+	valCtx := v.child()
+	valCtx.ctx = context.Background()
+	valCtx.errs = new(runErrors)
+	valCtx.mapElem = elem
+	valCtx.lookups = newLookupCache()
+	if len(message) > 0 {
+		valCtx.messages = message[0]
+	}
+	valCtx.batchLookups(func() ValidationErrors { return valCtx.mapValidator(rule) })
+	errs := valCtx.mapValidator(rule)
+	if err := valCtx.errs.get(); err != nil {
+		return err
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// child returns a validation context sharing the configuration of v.
+func (v *validation) child() *validation {
+	return &validation{
+		ctx:       v.ctx,
 		locale:    v.locale,
-		dbConfig:  v.dbConfig,
+		lookup:    v.lookup,
+		tables:    v.tables,
+		dbErr:     v.dbErr,
+		errs:      v.errs,
+		lookups:   v.lookups,
+		batch:     v.batch,
+		batching:  v.batching,
+		rules:     v.rules,
+		now:       v.now,
+		nameTag:   v.nameTag,
+		allErrors: v.allErrors,
 	}
+}
 
-	switch valCtx.elemType.Kind() {
-	case reflect.Struct:
-		return valCtx.structValidator()
+// newLookup returns the Lookup of config: Lookup itself, or a SQLLookup of
+// Querier or of the pool opened for DB, which is also returned to be closed.
+// Errors are returned by the validations using the database.
+func newLookup(config *Config) (Lookup, *sql.DB, error) {
+	if config.Lookup != nil {
+		return config.Lookup, nil, nil
+	}
+	db, driver := config.Querier, config.Driver
+	var pool *sql.DB
+	if db == nil && config.DB != nil {
+		var err error
+		if pool, err = config.DB.Open(); err != nil {
+			return nil, nil, err
+		}
+		db, driver = pool, config.DB.Driver
+	}
+	if db == nil {
+		return nil, nil, nil
+	}
+	dialect := config.Dialect
+	if dialect == nil {
+		if driver == "" {
+			// Guessing would send placeholders the database does not understand
+			return nil, pool, fmt.Errorf("%w of Config.Querier, set Config.Driver or Config.Dialect", ErrNoDialect)
+		}
+		var ok bool
+		if dialect, ok = DialectFor(driver); !ok {
+			return nil, pool, fmt.Errorf("%w for database driver %q, set Config.Dialect", ErrNoDialect, driver)
+		}
 	}
+	return &SQLLookup{DB: db, Dialect: dialect}, pool, nil
+}
 
-	return map[string]any{"error": "validate: a struct pointer is expected as an argument"}
+// Close closes the connection pool opened for Config.DB.
+func (v *validation) Close() error {
+	if v.pool == nil {
+		return nil
+	}
+	return v.pool.Close()
 }
 
-// ValidateMap performs validation on map.
-// It takes map pointer as parameter.
-func (v *validation) ValidateMap(elem map[string]any, rule map[string]string, message ...map[string]string) map[string]any {
-	// Return specific error instead of panic for production stability
-	return map[string]any{"error": "ValidateMap is not implemented"}
+// lookupFor returns the Lookup of the database rules.
+func (v *validation) lookupFor() (Lookup, error) {
+	if v.dbErr != nil {
+		return nil, v.dbErr
+	}
+	if v.lookup == nil {
+		return nil, ErrNoDatabase
+	}
+	return v.lookup, nil
+}
+
+// batchLookups runs validate to collect the lookups of the database rules, then
+// runs them together and caches the results for the validation, see
+// Config.BatchLookups. While collecting, database rules pass while custom and
+// file rules are skipped, so values failing another built-in rule first are
+// not looked up.
+func (v *validation) batchLookups(validate func() ValidationErrors) {
+	lookup, ok := v.lookup.(BatchLookup)
+	if !v.batching || !ok || v.dbErr != nil {
+		return
+	}
+	v.batch = new(lookupBatch)
+	validate()
+	batch := v.batch
+	v.batch = nil
+	if err := batch.run(v.ctx, lookup, v.lookups); err != nil {
+		v.errs.add(err)
+	}
+}
+
+// runErrors records the first error, other than failing rules, of a validation call,
+// e.g. a failed database query.
+type runErrors struct {
+	mu  sync.Mutex
+	err error
+}
+
+func (e *runErrors) add(err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.err == nil {
+		e.err = err
+	}
+}
+
+func (e *runErrors) get() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.err
 }
 
 // RequestStruct takes struct pointer as parameter.
@@ -138,90 +385,10 @@ func (v *validation) RequestStruct(elem any) Validator {
 
 // ValidateRequest performs validation on in coming request.
 // It is a middleware that takes http.Handler as parameter and return  http.Handler.
+// The validated struct is available to next through FromContext.
 func (v *validation) ValidateRequest(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Ensure body is closed
-		defer func() {
-			if r.Body != nil {
-				_ = r.Body.Close()
-			}
-		}()
-
-		// Create a new instance of the struct for this request to avoid race conditions
-		// v.elemType is safe to read as it is set during initialization
-		if v.elemType == nil {
-			writeJSONError(w, http.StatusInternalServerError, "validation struct type not initialized")
-			return
-		}
-
-		reqElem := reflect.New(v.elemType).Interface()
-
-		// Create a request-specific validation context
-		reqVal := &validation{
-			elem:      reqElem,
-			elemType:  v.elemType,
-			elemValue: reflect.ValueOf(reqElem).Elem(),
-			locale:    v.locale,
-			dbConfig:  v.dbConfig,
-		}
-
-		contentType := r.Header.Get("Content-Type")
-		if strings.HasPrefix(contentType, "multipart/form-data") || strings.HasPrefix(contentType, "application/x-www-form-urlencoded") {
-			if err := decodeMultipart(r, reqElem); err != nil {
-				writeJSONError(w, http.StatusBadRequest, fmt.Sprintf("failed to decode form: %s", err.Error()))
-				return
-			}
-		} else if strings.HasPrefix(contentType, "application/json") {
-			body, err := io.ReadAll(r.Body)
-			if err != nil {
-				writeJSONError(w, http.StatusBadRequest, fmt.Sprintf("failed to read body: %s", err.Error()))
-				return
-			}
-			if err := json.Unmarshal(body, reqElem); err != nil {
-				writeJSONError(w, http.StatusBadRequest, fmt.Sprintf("failed to unmarshal: %s", err.Error()))
-				return
-			}
-		} else if strings.HasPrefix(contentType, "text/xml") || strings.HasPrefix(contentType, "application/xml") {
-			body, err := io.ReadAll(r.Body)
-			if err != nil {
-				writeJSONError(w, http.StatusBadRequest, fmt.Sprintf("failed to read body: %s", err.Error()))
-				return
-			}
-			if err := xml.Unmarshal(body, reqElem); err != nil {
-				writeJSONError(w, http.StatusBadRequest, fmt.Sprintf("failed to unmarshal: %s", err.Error()))
-				return
-			}
-		} else {
-			writeJSONError(w, http.StatusBadRequest, fmt.Sprintf("content-type: %s, not supported.", contentType))
-			return
-		}
-
-		switch reqVal.elemType.Kind() {
-		case reflect.Struct:
-			message := reqVal.structValidator()
-			if len(message) > 0 {
-				reqVal.jsonRes.Errors = message
-			} else {
-				reqVal.jsonRes.Errors = nil
-			}
-		}
-
-		if reqVal.jsonRes.Errors != nil {
-			reqVal.jsonRes.Status = false
-			resByte, _ := json.Marshal(reqVal.jsonRes)
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusUnprocessableEntity)
-			_, _ = w.Write(resByte)
-			return
-		}
-
-		// Pass the validated struct to the next handler via context if needed,
-		// but for now we just proceed. The next handler might need to access the data.
-		// Since Go's http.Handler doesn't inherently pass the struct,
-		// the user typically accesses it via closure or context in real apps.
-		// Here we maintain the original design flow.
-		next.ServeHTTP(w, r)
-	})
+	// v.elemType is safe to read as it is set during initialization
+	return validateHandler(v, v.elemType, next, nil)
 }
 
 // Helper to write JSON errors consistently
@@ -235,36 +402,33 @@ func writeJSONError(w http.ResponseWriter, status int, msg string) {
 	_, _ = w.Write(resByte)
 }
 
-func (v *validation) structValidator() map[string]any {
-	mChan := make(chan message, v.elemType.NumField())
+func (v *validation) structValidator() ValidationErrors {
+	fields := structFields(v.elemType, v.elemValue, v.nameTag)
+	mChan := make(chan FieldError, len(fields))
 	wg := &sync.WaitGroup{}
 
-	for i := 0; i < v.elemType.NumField(); i++ {
-		// Check for json tag first
-		if _, ok := v.elemType.Field(i).Tag.Lookup("json"); !ok {
-			// Skip fields without json tag instead of panicking
+	for _, field := range fields {
+		// Skip unexported fields and fields named "-"
+		if !field.IsExported() || field.name == "" {
 			continue
 		}
-		// Check for validate tag
-		if _, ok := v.elemType.Field(i).Tag.Lookup("validate"); !ok {
+		// Check for validate tag, nested structs and non nil struct pointers are validated without one
+		if _, ok := field.Tag.Lookup("validate"); !ok && !isNestedValue(field.value) {
 			// Skip fields without validate tag
 			continue
 		}
 
 		wg.Add(1)
-		go func(index int) {
+		go func(field structField) {
 			defer wg.Done()
 			// Recover from panics in goroutines to prevent server crash
 			defer func() {
 				if err := recover(); err != nil {
-					mChan <- message{
-						K: v.elemType.Field(index).Tag.Get("json"),
-						V: fmt.Sprintf("validation panic: %v", err),
-					}
+					v.recovered(field.name, err)
 				}
 			}()
-			v.validateStruct(index, mChan)
-		}(i)
+			v.validateField(field.value, field.Tag.Get("validate"), field.name, mChan)
+		}(field)
 	}
 
 	go func() {
@@ -272,28 +436,154 @@ func (v *validation) structValidator() map[string]any {
 		close(mChan)
 	}()
 
-	errMsg := make(map[string]any)
-	for msg := range mChan {
-		// Only collect non-empty error messages
-		if msg.V != nil && msg.V != "" {
-			errMsg[msg.K] = msg.V
+	return collectErrors(mChan)
+}
+
+// collectErrors drains msgChan and returns the errors ordered by path.
+func collectErrors(msgChan chan FieldError) ValidationErrors {
+	var errs ValidationErrors
+	for fe := range msgChan {
+		errs = append(errs, fe)
+	}
+	errs.sort()
+	return errs
+}
+
+// structField is a field of a struct along with its value and the name
+// given by the name tag. name is empty for fields tagged "-".
+type structField struct {
+	reflect.StructField
+	value reflect.Value
+	name  string
+}
+
+// structFields returns the fields of the struct value of type t. The fields of
+// embedded structs without a name in nameTag are promoted as if declared
+// inline, unless a field with the same name shadows them.
+func structFields(t reflect.Type, value reflect.Value, nameTag string) []structField {
+	var fields, promoted []structField
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if name, _, _ := strings.Cut(field.Tag.Get(nameTag), ","); field.Anonymous && name == "" {
+			embeddedType, embeddedValue := field.Type, value.Field(i)
+			if embeddedType.Kind() == reflect.Pointer {
+				if embeddedValue.IsNil() {
+					continue
+				}
+				embeddedType, embeddedValue = embeddedType.Elem(), embeddedValue.Elem()
+			}
+			if isNestedStruct(embeddedType) {
+				promoted = append(promoted, structFields(embeddedType, embeddedValue, nameTag)...)
+				continue
+			}
 		}
+		fields = append(fields, structField{StructField: field, value: value.Field(i), name: fieldName(field, nameTag)})
 	}
+	for _, field := range promoted {
+		if !slices.ContainsFunc(fields, func(f structField) bool { return f.name == field.name }) {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// recovered records a panic of the rules of path as an error of the validation
+// call wrapping ErrPanic, instead of a failing field.
+func (v *validation) recovered(path string, err any) {
+	v.errs.add(fmt.Errorf("%w: %s: %v", ErrPanic, path, err))
+}
+
+func (v *validation) mapValidator(rules map[string]string) ValidationErrors {
+	mChan := make(chan FieldError, len(rules))
+	wg := &sync.WaitGroup{}
 
-	if len(errMsg) > 0 {
-		return errMsg
+	for key, rule := range rules {
+		wg.Add(1)
+		go func(key, rule string) {
+			defer wg.Done()
+			// Recover from panics in goroutines to prevent server crash
+			defer func() {
+				if err := recover(); err != nil {
+					v.recovered(key, err)
+				}
+			}()
+			// Missing keys and nil values produce an invalid reflect.Value, which isEmpty treats as empty
+			v.validateField(reflect.ValueOf(v.mapElem[key]), rule, key, mChan)
+		}(key, rule)
 	}
-	return nil
+
+	go func() {
+		wg.Wait()
+		close(mChan)
+	}()
+
+	return collectErrors(mChan)
 }
 
-func (v *validation) validateStruct(index int, msgChan chan message) {
-	ruleOrMsgs := strings.Split(v.elemType.Field(index).Tag.Get("validate"), "|")
-	value := v.elemValue.Field(index)
-	jsonTag := v.elemType.Field(index).Tag.Get("json")
-	formattedField := formatFieldName(jsonTag)
+func (v *validation) validateField(value reflect.Value, rules, jsonTag string, msgChan chan FieldError) {
+	v.validateRules(value, rules, jsonTag, v.fieldLabel(jsonTag), msgChan)
+}
 
+// fieldLabel returns the name of a field in messages: its label registered for
+// the locale, its label tag, e.g. `label:"Date of birth"`, or its formatted name.
+func (v *validation) fieldLabel(name string) string {
+	if label, ok := locale.Label(v.locale, name); ok {
+		return label
+	}
+	if v.mapElem == nil && v.elemType != nil {
+		for _, field := range structFields(v.elemType, v.elemValue, v.nameTag) {
+			if field.name == name {
+				if label := field.Tag.Get("label"); label != "" {
+					return label
+				}
+				break
+			}
+		}
+	}
+	return formatFieldName(name)
+}
+
+// validateRules runs the pipeline of rules on value, stopping at the first failing rule
+// unless AllErrors is set. formattedField is the name of the value in messages.
+func (v *validation) validateRules(value reflect.Value, rules, jsonTag, formattedField string, msgChan chan FieldError) {
+	ruleOrMsgs := strings.Split(rules, "|")
+	var keyRules, valueRules []string
+	if value.Kind() == reflect.Map {
+		ruleOrMsgs, keyRules, valueRules = splitMapRules(ruleOrMsgs)
+	}
+
+	// In AllErrors mode every failing rule is reported, unless the field has the bail rule
+	bail := !v.allErrors || slices.Contains(ruleOrMsgs, "bail")
+	failed := false
 	for _, ruleOrMsg := range ruleOrMsgs {
+		if v.ctx.Err() != nil {
+			// ValidateStructCtx reports the cancellation
+			return
+		}
 		rule, customMsg := getRuleAndMsg(ruleOrMsg)
+		if rule == "" || rule == "bail" {
+			continue
+		}
+		if customMsg == "" {
+			customMsg = v.customMessage(jsonTag, rule)
+		}
+
+		// The each modifier applies the rule to every element of a slice
+		if elemRule, ok := strings.CutPrefix(rule, "each:"); ok {
+			if v.validateEach(value, elemRule, customMsg, jsonTag, formattedField, msgChan) {
+				if bail {
+					return
+				}
+				failed = true
+			}
+			continue
+		}
+
+		// Conditional rules look at sibling fields and apply to empty values as well.
+		// They end the pipeline as the other rules skip empty or excluded values.
+		if v.validateConditional(value, rule, customMsg, jsonTag, formattedField, msgChan) {
+			return
+		}
 
 		// Handle Required Check
 		if rule == "required" && isEmpty(value) {
@@ -305,37 +595,102 @@ func (v *validation) validateStruct(index int, msgChan chan message) {
 			return
 		}
 
-		if !isEmpty(value) {
-			switch value.Kind() {
-			case reflect.String:
-				if v.validateString(value, rule, customMsg, jsonTag, formattedField, msgChan) {
-					return
-				}
-			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
-				if v.validateNumeric(value, rule, customMsg, jsonTag, formattedField, msgChan) {
-					return
-				}
-			case reflect.Float32, reflect.Float64:
-				if v.validateFloat(value, rule, customMsg, jsonTag, formattedField, msgChan) {
-					return
-				}
-			case reflect.Slice, reflect.Array:
-				if v.validateSlice(value, rule, customMsg, jsonTag, formattedField, msgChan) {
-					return
-				}
-			case reflect.Pointer, reflect.Interface:
-				if v.validatePointer(value, rule, customMsg, jsonTag, formattedField, msgChan) {
-					return
-				}
+		if !isEmpty(value) && v.validateRule(value, rule, customMsg, jsonTag, formattedField, msgChan) {
+			if bail {
+				return
+			}
+			failed = true
+		}
+	}
+	if failed {
+		return
+	}
+
+	if len(keyRules) > 0 || len(valueRules) > 0 {
+		v.validateMapEntries(value, strings.Join(keyRules, "|"), strings.Join(valueRules, "|"), jsonTag, formattedField, msgChan)
+	}
+	if !slices.ContainsFunc(ruleOrMsgs, isEachRule) {
+		// Nested structs are validated once the rules of the value pass, elements
+		// with each rules validate their own
+		for _, fe := range v.nestedErrors(value, jsonTag) {
+			msgChan <- fe
+		}
+	}
+}
+
+// nestedErrors validates the structs nested in value: a struct, a struct pointer or
+// the struct elements of a slice. Paths are prefixed with jsonTag, e.g. "address.city"
+// or "contacts.1.email".
+func (v *validation) nestedErrors(value reflect.Value, jsonTag string) ValidationErrors {
+	switch value.Kind() {
+	case reflect.Struct, reflect.Pointer:
+		if isNestedValue(value) {
+			return v.validateNested(value).prefixed(jsonTag)
+		}
+	case reflect.Slice, reflect.Array:
+		var errs ValidationErrors
+		for i := 0; i < value.Len(); i++ {
+			elemVal := value.Index(i)
+			if elemVal.Kind() == reflect.Interface {
+				elemVal = elemVal.Elem()
+			}
+			if isNestedValue(elemVal) {
+				errs = append(errs, v.validateNested(elemVal).prefixed(jsonTag+"."+strconv.Itoa(i))...)
 			}
 		}
+		return errs
+	}
+	return nil
+}
+
+// validateRule runs rule on the non empty value and reports whether it failed.
+func (v *validation) validateRule(value reflect.Value, rule, customMsg, jsonTag, formattedField string, msgChan chan FieldError) bool {
+	// Custom rules apply to every kind and take precedence over built-in ones
+	if name, params, cr, ok := v.rules.lookup(rule); ok {
+		if v.batch != nil {
+			// Custom rules run once, in the validation pass
+			return false
+		}
+		if !cr.fn(v.ctx, value, params) {
+			v.setMessage(name, customMsg, jsonTag, formattedField, msgChan, params...)
+			return true
+		}
+		return false
+	}
+	if v.batch != nil && value.Type() == fileHeaderType {
+		// File rules read the uploads, they run once in the validation pass
+		return false
+	}
+	// Type rules fail on scalar values of other kinds, slices check their elements
+	if accepts, ok := typeRules[rule]; ok && isScalarKind(value.Kind()) && !accepts(value.Kind()) {
+		msgKey := rule
+		if rule == "ascii" {
+			msgKey = "string"
+		}
+		v.setMessage(msgKey, customMsg, jsonTag, formattedField, msgChan)
+		return true
+	}
+	switch value.Kind() {
+	case reflect.String:
+		return v.validateString(value, rule, customMsg, jsonTag, formattedField, msgChan)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.validateNumeric(value, rule, customMsg, jsonTag, formattedField, msgChan)
+	case reflect.Float32, reflect.Float64:
+		return v.validateFloat(value, rule, customMsg, jsonTag, formattedField, msgChan)
+	case reflect.Slice, reflect.Array:
+		return v.validateSlice(value, rule, customMsg, jsonTag, formattedField, msgChan)
+	case reflect.Pointer, reflect.Interface:
+		return v.validatePointer(value, rule, customMsg, jsonTag, formattedField, msgChan)
+	case reflect.Struct:
+		return v.validateTime(value, rule, customMsg, jsonTag, formattedField, msgChan)
+	case reflect.Map:
+		return v.validateMapSize(value, rule, customMsg, jsonTag, formattedField, msgChan)
 	}
-	// If no rules triggered a return (error), send success/empty message
-	v.setMessage("empty", "", jsonTag, formattedField, msgChan)
+	return false
 }
 
-// Helper methods to break down validateStruct for readability and maintenance
-func (v *validation) validateString(value reflect.Value, rule, customMsg, jsonTag, formattedField string, msgChan chan message) bool {
+// Helper methods to break down validateField for readability and maintenance
+func (v *validation) validateString(value reflect.Value, rule, customMsg, jsonTag, formattedField string, msgChan chan FieldError) bool {
 	switch rule {
 	case "string":
 		if isNotString(value) {
@@ -405,7 +760,7 @@ func (v *validation) validateString(value reflect.Value, rule, customMsg, jsonTa
 	return false
 }
 
-func (v *validation) validateStringParams(value reflect.Value, rule, customMsg, jsonTag, formattedField string, msgChan chan message) bool {
+func (v *validation) validateStringParams(value reflect.Value, rule, customMsg, jsonTag, formattedField string, msgChan chan FieldError) bool {
 	rSlice := strings.SplitN(rule, ":", 2)
 	switch rSlice[0] {
 	case "min":
@@ -458,18 +813,156 @@ func (v *validation) validateStringParams(value reflect.Value, rule, customMsg,
 			v.setMessage("match", customMsg, jsonTag, formattedField, msgChan)
 			return true
 		}
-	case "unique":
-		if tc := strings.SplitN(rSlice[1], ".", 2); len(tc) == 2 {
-			if isNotUnique(v.dbConfig, value.String(), tc[1], tc[0]) {
-				v.setMessage("unique", customMsg, jsonTag, formattedField, msgChan)
-				return true
+	case "gt", "gte", "lt", "lte":
+		return v.validateComparison(value, rSlice[0], rSlice[1], "string", customMsg, jsonTag, formattedField, msgChan)
+	case "before", "after", "before_or_equal", "after_or_equal", "before_field", "after_field", "age_min", "age_max", "within":
+		return v.validateDate(value, rule, customMsg, jsonTag, formattedField, msgChan)
+	case "unique", "exists":
+		return v.validateDatabase(value, rSlice[0], rSlice[1], customMsg, jsonTag, formattedField, msgChan)
+	}
+	return false
+}
+
+// validateDatabase handles the rules querying the database, see Lookup. Their
+// parameters start with table.column or schema.table.column:
+//   - unique accepts ignore=@id (or ignore=column:@field) to skip the row being
+//     updated, scope=column:@field to check within the value of a sibling field,
+//     or within the rows where column is NULL when the sibling is empty,
+//     and ci for case-insensitive matching, e.g. "unique:users.email,ignore=@id,ci".
+//   - exists accepts column/value pairs where NULL and NOT_NULL match null and non
+//     null columns, e.g. "exists:users.id,deleted_at,NULL".
+//
+// Query errors are returned by the validation and end the pipeline of the field.
+// Identical lookups of a validation run once.
+func (v *validation) validateDatabase(value reflect.Value, rule, param, customMsg, jsonTag, formattedField string, msgChan chan FieldError) bool {
+	q, err := v.lookupQuery(value, rule, param)
+	if v.batch != nil {
+		// Collecting the lookups of Config.BatchLookups, errors are reported by the validation
+		if err == nil && q != nil {
+			v.batch.add(*q)
+		}
+		return false
+	}
+	if err != nil {
+		v.errs.add(err)
+		return true
+	}
+	if q == nil {
+		return false
+	}
+	lookup, err := v.lookupFor()
+	if err != nil {
+		v.errs.add(err)
+		return true
+	}
+	found, err := v.lookups.exists(v.ctx, lookup, *q)
+	if err != nil {
+		v.errs.add(fmt.Errorf("validate: %s %s.%s: %w", rule, q.resource, q.field, err))
+		return true
+	}
+	// unique fails on existing entries, exists on missing ones
+	failed := found == (rule == "unique")
+	if failed {
+		v.setMessage(rule, customMsg, jsonTag, formattedField, msgChan)
+	}
+	return failed
+}
+
+// lookupQuery returns the lookup of a database rule on value, or nil when its
+// parameter has no column.
+func (v *validation) lookupQuery(value reflect.Value, rule, param string) (*lookupQuery, error) {
+	params := strings.Split(param, ",")
+	i := strings.LastIndex(params[0], ".")
+	if i < 0 {
+		return nil, nil
+	}
+	table, column := params[0][:i], params[0][i+1:]
+	if len(v.tables) > 0 && !slices.Contains(v.tables, table) {
+		return nil, fmt.Errorf("%w: %q", ErrTableNotAllowed, table)
+	}
+	var conditions []Condition
+	var err error
+	if rule == "unique" {
+		conditions, err = v.uniqueConditions(column, params[1:])
+	} else {
+		conditions, err = existsConditions(params[1:])
+	}
+	if err != nil {
+		return nil, fmt.Errorf("validate: %s %s: %w", rule, params[0], err)
+	}
+	return &lookupQuery{resource: table, field: column, value: value.Interface(), conditions: conditions}, nil
+}
+
+// uniqueConditions returns the conditions of the ignore, scope and ci options of
+// the unique rule checking column.
+func (v *validation) uniqueConditions(column string, options []string) ([]Condition, error) {
+	var conditions []Condition
+	for _, option := range options {
+		name, arg, _ := strings.Cut(option, "=")
+		switch name {
+		case "ci":
+			conditions = append(conditions, Condition{Field: column, Op: OpEqualFold})
+		case "ignore":
+			field, ref, ok := strings.Cut(arg, ":")
+			if !ok {
+				field, ref = "id", arg
+			}
+			// Nothing to ignore when creating a row
+			if val, ok := v.paramValue(ref); ok {
+				conditions = append(conditions, Condition{Field: field, Op: OpNotEqual, Value: val})
+			}
+		case "scope":
+			field, ref, ok := strings.Cut(arg, ":")
+			if !ok {
+				return nil, fmt.Errorf("scope %q needs a column and a value", arg)
+			}
+			// An empty sibling scopes the check to the rows without one, = NULL would match none
+			if val, ok := v.paramValue(ref); ok {
+				conditions = append(conditions, Condition{Field: field, Op: OpEqual, Value: val})
+			} else {
+				conditions = append(conditions, Condition{Field: field, Op: OpNull})
 			}
+		default:
+			return nil, fmt.Errorf("unknown option %q", option)
 		}
 	}
-	return false
+	return conditions, nil
 }
 
-func (v *validation) validateNumeric(value reflect.Value, rule, customMsg, jsonTag, formattedField string, msgChan chan message) bool {
+// existsConditions returns the conditions of the column/value pairs of the exists rule.
+func existsConditions(pairs []string) ([]Condition, error) {
+	if len(pairs)%2 != 0 {
+		return nil, errors.New("where clauses need column and value pairs")
+	}
+	conditions := make([]Condition, 0, len(pairs)/2)
+	for i := 0; i < len(pairs); i += 2 {
+		switch pairs[i+1] {
+		case "NULL":
+			conditions = append(conditions, Condition{Field: pairs[i], Op: OpNull})
+		case "NOT_NULL":
+			conditions = append(conditions, Condition{Field: pairs[i], Op: OpNotNull})
+		default:
+			conditions = append(conditions, Condition{Field: pairs[i], Op: OpEqual, Value: pairs[i+1]})
+		}
+	}
+	return conditions, nil
+}
+
+// paramValue returns the value of a rule parameter: the value of a sibling field
+// for @field, reporting false when it is empty, or the parameter itself.
+func (v *validation) paramValue(param string) (any, bool) {
+	tag, ok := strings.CutPrefix(param, "@")
+	if !ok {
+		return param, true
+	}
+	_, val := v.getTagAndValue(tag)
+	if isEmpty(val) {
+		return nil, false
+	}
+	return val.Interface(), true
+}
+
+func (v *validation) validateNumeric(value reflect.Value, rule, customMsg, jsonTag, formattedField string, msgChan chan FieldError) bool {
 	switch rule {
 	case "int":
 		if isNotInt(value) {
@@ -489,7 +982,7 @@ func (v *validation) validateNumeric(value reflect.Value, rule, customMsg, jsonT
 	return false
 }
 
-func (v *validation) validateNumericParams(value reflect.Value, rule, customMsg, jsonTag, formattedField string, msgChan chan message) bool {
+func (v *validation) validateNumericParams(value reflect.Value, rule, customMsg, jsonTag, formattedField string, msgChan chan FieldError) bool {
 	rSlice := strings.SplitN(rule, ":", 2)
 	switch rSlice[0] {
 	case "min", "max", "equal", "size":
@@ -521,6 +1014,10 @@ func (v *validation) validateNumericParams(value reflect.Value, rule, customMsg,
 			v.setMessage("between.numeric", customMsg, jsonTag, formattedField, msgChan, minMax[0], minMax[1])
 			return true
 		}
+	case "gt", "gte", "lt", "lte":
+		return v.validateComparison(value, rSlice[0], rSlice[1], "numeric", customMsg, jsonTag, formattedField, msgChan)
+	case "unique", "exists":
+		return v.validateDatabase(value, rSlice[0], rSlice[1], customMsg, jsonTag, formattedField, msgChan)
 	case "enum":
 		enums := strings.Split(rSlice[1], ",")
 		if isNotEnum(value, enums) {
@@ -543,8 +1040,19 @@ func (v *validation) validateNumericParams(value reflect.Value, rule, customMsg,
 	return false
 }
 
-func (v *validation) validateFloat(value reflect.Value, rule, customMsg, jsonTag, formattedField string, msgChan chan message) bool {
+func (v *validation) validateFloat(value reflect.Value, rule, customMsg, jsonTag, formattedField string, msgChan chan FieldError) bool {
 	switch rule {
+	case "int":
+		// JSON numbers decoded into map[string]any are always float64
+		if value.Float() != math.Trunc(value.Float()) {
+			v.setMessage("int", customMsg, jsonTag, formattedField, msgChan)
+			return true
+		}
+	case "uint":
+		if value.Float() < 0 || value.Float() != math.Trunc(value.Float()) {
+			v.setMessage("uint", customMsg, jsonTag, formattedField, msgChan)
+			return true
+		}
 	case "float":
 		if isNotFloat(value) {
 			v.setMessage("float", customMsg, jsonTag, formattedField, msgChan)
@@ -558,7 +1066,7 @@ func (v *validation) validateFloat(value reflect.Value, rule, customMsg, jsonTag
 	return false
 }
 
-func (v *validation) validateSlice(value reflect.Value, rule, customMsg, jsonTag, formattedField string, msgChan chan message) bool {
+func (v *validation) validateSlice(value reflect.Value, rule, customMsg, jsonTag, formattedField string, msgChan chan FieldError) bool {
 	if strings.HasPrefix(rule, "slice") && strings.Contains(rule, ":") {
 		rSlice := strings.SplitN(rule, ":", 3)[1:]
 		if len(rSlice) >= 2 {
@@ -570,70 +1078,62 @@ func (v *validation) validateSlice(value reflect.Value, rule, customMsg, jsonTag
 				v.setMessage("max.slice", customMsg, jsonTag, formattedField, msgChan, rSlice[1])
 				return true
 			}
+			if isComparison(rSlice[0]) {
+				return v.validateComparison(value, rSlice[0], rSlice[1], "slice", customMsg, jsonTag, formattedField, msgChan)
+			}
+		}
+	}
+
+	// Comparisons apply to the number of items, except for files which compare each file size
+	if op, param, _ := strings.Cut(rule, ":"); isComparison(op) {
+		if _, ok := value.Interface().([]*multipart.FileHeader); !ok {
+			return v.validateComparison(value, op, param, "slice", customMsg, jsonTag, formattedField, msgChan)
 		}
 	}
 
 	// Validate slice elements
-	errMsgs := make([]any, 0, value.Len())
-	elemKind := value.Type().Elem().Kind()
+	var errs ValidationErrors
 
 	for i := 0; i < value.Len(); i++ {
 		elemVal := value.Index(i)
+		if elemVal.Kind() == reflect.Interface {
+			// Elements of []any (e.g. decoded JSON in ValidateMap) carry their concrete kind
+			elemVal = elemVal.Elem()
+		}
 		fieldName := fmt.Sprintf("%s (%d)", formattedField, i+1)
+		elemPath := jsonTag + "." + strconv.Itoa(i)
 		hasError := false
 
 		// Simplified element validation logic mirroring single value validation
 		// In production, this should ideally recurse or call specific type validators
-		switch elemKind {
+		switch elemVal.Kind() {
 		case reflect.String:
 			// Example for string slice
 			if rule == "email" && isNotEmail(elemVal) {
-				errMsgs = append(errMsgs, v.generateMessage("email", customMsg, fieldName))
+				errs = append(errs, v.elementError("email", customMsg, jsonTag, elemPath, fieldName))
 				hasError = true
 			}
 			// Add other string rules as needed...
-		case reflect.Pointer, reflect.Interface:
-			if _, ok := elemVal.Interface().([]*multipart.FileHeader); ok {
+		case reflect.Pointer:
+			if fh, ok := elemVal.Interface().(*multipart.FileHeader); ok {
 				// File validation logic (simplified for brevity)
 				if rule == "image" {
 					if isNotMimes(elemVal, "jpg,jpeg,png,webp") {
-						errMsgs = append(errMsgs, v.generateMessage("image", customMsg, fieldName))
+						errs = append(errs, v.elementError("image", customMsg, jsonTag, elemPath, fieldName))
 						hasError = true
 					}
 				}
-				// Handle size rule with precompiled regex
-				if strings.HasPrefix(rule, "size:") {
-					rSlice := strings.SplitN(rule, ":", 2)
-					matches := fileSizeRegex.FindAllStringSubmatch(rSlice[1], -1)
-					if len(matches) > 0 {
-						size, symbol := matches[0][1], matches[0][2]
-						size64, _ := strconv.ParseInt(size, 10, 64)
-						if fh, ok := elemVal.Interface().(*multipart.FileHeader); ok {
-							limit := int64(0)
-							msgKey := ""
-							switch strings.ToLower(symbol) {
-							case "kb":
-								limit = kilobyte * size64
-								msgKey = "size.file_kb"
-							case "mb":
-								limit = megabyte * size64
-								msgKey = "size.file_mb"
-							case "gb":
-								limit = gigabyte * size64
-								msgKey = "size.file_gb"
-							}
-							if limit > 0 && fh.Size > limit {
-								errMsgs = append(errMsgs, v.generateMessage(msgKey, customMsg, fieldName, size))
-								hasError = true
-							}
-						}
+				// Handle size rules with precompiled regex
+				op, param, _ := strings.Cut(rule, ":")
+				if limit, size, kind, ok := parseFileSize(param); ok {
+					if op == "size" && fh.Size > limit {
+						errs = append(errs, v.elementError("size."+kind, customMsg, jsonTag, elemPath, fieldName, size))
+						hasError = true
+					}
+					if isComparison(op) && failsComparison(op, cmp.Compare(fh.Size, limit)) {
+						errs = append(errs, v.elementError(op+"."+kind, customMsg, jsonTag, elemPath, fieldName, size))
+						hasError = true
 					}
-				}
-			} else {
-				// Struct pointer in slice
-				if msg := New(&Config{Locale: v.locale, DB: v.dbConfig}).ValidateStruct(elemVal.Interface()); msg != nil {
-					errMsgs = append(errMsgs, msg)
-					hasError = true
 				}
 			}
 		}
@@ -644,16 +1144,21 @@ func (v *validation) validateSlice(value reflect.Value, rule, customMsg, jsonTag
 		}
 	}
 
-	if len(errMsgs) > 0 {
-		v.setMessage("", errMsgs, jsonTag, formattedField, msgChan)
+	if len(errs) > 0 {
+		for _, fe := range errs {
+			msgChan <- fe
+		}
 		return true
 	}
 
 	return false
 }
 
-func (v *validation) validatePointer(value reflect.Value, rule, customMsg, jsonTag, formattedField string, msgChan chan message) bool {
-	if _, ok := value.Interface().(*multipart.FileHeader); ok {
+func (v *validation) validatePointer(value reflect.Value, rule, customMsg, jsonTag, formattedField string, msgChan chan FieldError) bool {
+	if t, ok := value.Interface().(*time.Time); ok {
+		return v.validateTime(reflect.ValueOf(*t), rule, customMsg, jsonTag, formattedField, msgChan)
+	}
+	if fh, ok := value.Interface().(*multipart.FileHeader); ok {
 		switch rule {
 		case "image":
 			if isNotMimes(value, "jpg,jpeg,png,webp") {
@@ -685,41 +1190,304 @@ func (v *validation) validatePointer(value reflect.Value, rule, customMsg, jsonT
 						return true
 					}
 				case "size":
-					matches := fileSizeRegex.FindAllStringSubmatch(rSlice[1], -1)
-					if len(matches) > 0 {
-						size, symbol := matches[0][1], matches[0][2]
-						size64, _ := strconv.ParseInt(size, 10, 64)
-						fh := value.Interface().(*multipart.FileHeader)
-						limit := int64(0)
-						msgKey := ""
-						switch strings.ToLower(symbol) {
-						case "kb":
-							limit = kilobyte * size64
-							msgKey = "size.file_kb"
-						case "mb":
-							limit = megabyte * size64
-							msgKey = "size.file_mb"
-						case "gb":
-							limit = gigabyte * size64
-							msgKey = "size.file_gb"
+					if limit, size, kind, ok := parseFileSize(rSlice[1]); ok {
+						if fh.Size > limit {
+							v.setMessage("size."+kind, customMsg, jsonTag, formattedField, msgChan, size)
+							return true
 						}
-						if limit > 0 && fh.Size > limit {
-							v.setMessage(msgKey, customMsg, jsonTag, formattedField, msgChan, size)
+					}
+				case "gt", "gte", "lt", "lte":
+					if limit, size, kind, ok := parseFileSize(rSlice[1]); ok {
+						if failsComparison(rSlice[0], cmp.Compare(fh.Size, limit)) {
+							v.setMessage(rSlice[0]+"."+kind, customMsg, jsonTag, formattedField, msgChan, size)
 							return true
 						}
 					}
 				}
 			}
 		}
-	} else {
-		if msg := New(&Config{Locale: v.locale, DB: v.dbConfig}).ValidateStruct(value.Interface()); msg != nil {
-			v.setMessage("", msg, jsonTag, formattedField, msgChan)
+	}
+	// Pointers to scalars, e.g. optional *string fields, are validated by the value they point to
+	if elem := value.Elem(); isScalarKind(elem.Kind()) {
+		return !isEmpty(elem) && v.validateRule(elem, rule, customMsg, jsonTag, formattedField, msgChan)
+	}
+	return false
+}
+
+// validateComparison handles the gt, gte, lt and lte rules. param is either a number
+// or a sibling field prefixed with @, e.g. "gt:@startDate". kind selects the message
+// used for numbers, e.g. "string" for "The name must be greater than 3 characters.".
+func (v *validation) validateComparison(value reflect.Value, op, param, kind, customMsg, jsonTag, formattedField string, msgChan chan FieldError) bool {
+	if tag, ok := strings.CutPrefix(param, "@"); ok {
+		_, other := v.getTagAndValue(tag)
+		if isEmpty(other) {
+			// Nothing to compare with, required rules report missing fields
+			return false
+		}
+		if isNotComparedTo(value, other, op) {
+			v.setMessage(op+".numeric", customMsg, jsonTag, formattedField, msgChan, v.fieldLabel(tag))
+			return true
+		}
+		return false
+	}
+	if isNotCompared(value, op, param) {
+		v.setMessage(op+"."+kind, customMsg, jsonTag, formattedField, msgChan, param)
+		return true
+	}
+	return false
+}
+
+// validateEach applies rule to every element of the slice value, e.g. "each:min:6".
+// Errors are reported under the element index, e.g. "items.2".
+func (v *validation) validateEach(value reflect.Value, rule, customMsg, jsonTag, formattedField string, msgChan chan FieldError) bool {
+	if value.Kind() != reflect.Slice && value.Kind() != reflect.Array {
+		return false
+	}
+	if customMsg != "" {
+		rule += ">" + customMsg
+	}
+	hasError := false
+	for i := 0; i < value.Len(); i++ {
+		elemVal := value.Index(i)
+		if elemVal.Kind() == reflect.Interface {
+			// Elements of []any (e.g. decoded JSON in ValidateMap) carry their concrete kind
+			elemVal = elemVal.Elem()
+		}
+		elemPath := jsonTag + "." + strconv.Itoa(i)
+		if v.validateElement(elemVal, rule, jsonTag, elemPath, fmt.Sprintf("%s (%d)", formattedField, i+1), msgChan) {
+			hasError = true
+		}
+	}
+	return hasError
+}
+
+// validateMapSize handles the rules on the number of entries of a map.
+func (v *validation) validateMapSize(value reflect.Value, rule, customMsg, jsonTag, formattedField string, msgChan chan FieldError) bool {
+	rSlice := strings.SplitN(rule, ":", 2)
+	if len(rSlice) != 2 {
+		return false
+	}
+	switch rSlice[0] {
+	case "min":
+		if isNotMin(value, rSlice[1]) {
+			v.setMessage("min.slice", customMsg, jsonTag, formattedField, msgChan, rSlice[1])
+			return true
+		}
+	case "max":
+		if isNotMax(value, rSlice[1]) {
+			v.setMessage("max.slice", customMsg, jsonTag, formattedField, msgChan, rSlice[1])
+			return true
+		}
+	case "size":
+		if isNotSize(value, rSlice[1]) {
+			v.setMessage("size.slice", customMsg, jsonTag, formattedField, msgChan, rSlice[1])
+			return true
+		}
+	case "between":
+		minMax := strings.SplitN(rSlice[1], ",", 2)
+		if len(minMax) == 2 && isNotBetween(value, minMax[0], minMax[1]) {
+			v.setMessage("between.slice", customMsg, jsonTag, formattedField, msgChan, minMax[0], minMax[1])
+			return true
+		}
+	case "from":
+		minMax := strings.SplitN(rSlice[1], ",", 2)
+		if len(minMax) == 2 && isNotFrom(value, minMax[0], minMax[1]) {
+			v.setMessage("from.slice", customMsg, jsonTag, formattedField, msgChan, minMax[0], minMax[1])
 			return true
 		}
+	case "gt", "gte", "lt", "lte":
+		return v.validateComparison(value, rSlice[0], rSlice[1], "slice", customMsg, jsonTag, formattedField, msgChan)
 	}
 	return false
 }
 
+// validateMapEntries applies keyRules to the keys and valueRules to the values of
+// the map value. Errors are reported under the entry key, e.g. "labels.env".
+func (v *validation) validateMapEntries(value reflect.Value, keyRules, valueRules, jsonTag, formattedField string, msgChan chan FieldError) {
+	keys := value.MapKeys()
+	slices.SortFunc(keys, func(a, b reflect.Value) int {
+		return strings.Compare(fmt.Sprint(a.Interface()), fmt.Sprint(b.Interface()))
+	})
+	for _, key := range keys {
+		name := fmt.Sprint(key.Interface())
+		entryPath := jsonTag + "." + name
+		if keyRules != "" && v.validateElement(key, keyRules, jsonTag, entryPath, fmt.Sprintf("%s key (%s)", formattedField, name), msgChan) {
+			continue
+		}
+		if valueRules != "" {
+			entryVal := value.MapIndex(key)
+			if entryVal.Kind() == reflect.Interface {
+				entryVal = entryVal.Elem()
+			}
+			v.validateElement(entryVal, valueRules, jsonTag, entryPath, fmt.Sprintf("%s (%s)", formattedField, name), msgChan)
+		}
+	}
+}
+
+// validateElement runs rules on an element of a slice or map and reports its
+// errors under path. formattedField is the name of the element in messages.
+func (v *validation) validateElement(value reflect.Value, rules, jsonTag, path, formattedField string, msgChan chan FieldError) bool {
+	elemChan := make(chan FieldError)
+	go func() {
+		defer close(elemChan)
+		// Recover from panics in goroutines to prevent server crash
+		defer func() {
+			if err := recover(); err != nil {
+				v.recovered(path, err)
+			}
+		}()
+		v.validateRules(value, rules, jsonTag, formattedField, elemChan)
+	}()
+	hasError := false
+	for fe := range elemChan {
+		fe.Path = path + strings.TrimPrefix(fe.Path, jsonTag)
+		msgChan <- fe
+		hasError = true
+	}
+	return hasError
+}
+
+// validateTime handles time.Time values, which support the date rules and
+// comparisons with other date fields, e.g. "gt:@startDate".
+func (v *validation) validateTime(value reflect.Value, rule, customMsg, jsonTag, formattedField string, msgChan chan FieldError) bool {
+	if _, ok := value.Interface().(time.Time); !ok {
+		return false
+	}
+	if op, param, _ := strings.Cut(rule, ":"); isComparison(op) {
+		return v.validateComparison(value, op, param, "numeric", customMsg, jsonTag, formattedField, msgChan)
+	}
+	return v.validateDate(value, rule, customMsg, jsonTag, formattedField, msgChan)
+}
+
+// validateDate handles the date rules of date strings and time.Time values.
+// Dates are compared with a date literal, now, today, tomorrow or yesterday
+// (before, after, before_or_equal, after_or_equal), with a sibling field
+// (before_field, after_field), with an age in years (age_min, age_max) or
+// with a duration around now such as 30d or 12h (within).
+func (v *validation) validateDate(value reflect.Value, rule, customMsg, jsonTag, formattedField string, msgChan chan FieldError) bool {
+	op, param, ok := strings.Cut(rule, ":")
+	if !ok {
+		return false
+	}
+	t, isDate := timeOf(value)
+	now := v.now()
+	switch op {
+	case "before", "after", "before_or_equal", "after_or_equal":
+		limit, ok := parseDateParam(param, now)
+		if !ok {
+			return false
+		}
+		if !isDate || isNotDateCompared(op, t, limit) {
+			v.setMessage(op, customMsg, jsonTag, formattedField, msgChan, param)
+			return true
+		}
+	case "before_field", "after_field":
+		_, other := v.getTagAndValue(param)
+		limit, ok := timeOf(other)
+		if !ok {
+			// Nothing to compare with, required rules report missing fields
+			return false
+		}
+		if !isDate || isNotDateCompared(strings.TrimSuffix(op, "_field"), t, limit) {
+			v.setMessage(op, customMsg, jsonTag, formattedField, msgChan, v.fieldLabel(param))
+			return true
+		}
+	case "age_min", "age_max":
+		years, err := strconv.Atoi(param)
+		if err != nil {
+			return false
+		}
+		if !isDate || (op == "age_min" && age(t, now) < years) || (op == "age_max" && age(t, now) > years) {
+			v.setMessage(op, customMsg, jsonTag, formattedField, msgChan, param)
+			return true
+		}
+	case "within":
+		d, ok := parseDuration(param)
+		if !ok {
+			return false
+		}
+		if !isDate || now.Sub(t).Abs() > d {
+			v.setMessage(op, customMsg, jsonTag, formattedField, msgChan, param)
+			return true
+		}
+	}
+	return false
+}
+
+// validateConditional handles the rules depending on sibling fields:
+// required_if, required_unless, required_with, required_without, prohibited_if
+// and excluded_if. It returns true when the remaining rules must be skipped,
+// either because one failed or because excluded_if matched.
+func (v *validation) validateConditional(value reflect.Value, rule, customMsg, jsonTag, formattedField string, msgChan chan FieldError) bool {
+	rSlice := strings.SplitN(rule, ":", 2)
+	if len(rSlice) != 2 {
+		return false
+	}
+	params := strings.Split(rSlice[1], ",")
+	switch rSlice[0] {
+	case "required_if", "required_unless", "prohibited_if", "excluded_if":
+		_, other := v.getTagAndValue(params[0])
+		matched := isAnyOf(other, params[1:])
+		values := strings.Join(params[1:], ",")
+		switch rSlice[0] {
+		case "required_if":
+			if matched && isEmpty(value) {
+				v.setMessage("required_if", customMsg, jsonTag, formattedField, msgChan, v.fieldLabel(params[0]), values)
+				return true
+			}
+		case "required_unless":
+			if !matched && isEmpty(value) {
+				v.setMessage("required_unless", customMsg, jsonTag, formattedField, msgChan, v.fieldLabel(params[0]), values)
+				return true
+			}
+		case "prohibited_if":
+			if matched && !isEmpty(value) {
+				v.setMessage("prohibited_if", customMsg, jsonTag, formattedField, msgChan, v.fieldLabel(params[0]), values)
+				return true
+			}
+		case "excluded_if":
+			return matched
+		}
+	case "required_with", "required_without":
+		if !isEmpty(value) {
+			return false
+		}
+		names := make([]string, 0, len(params))
+		for _, tag := range params {
+			_, other := v.getTagAndValue(tag)
+			if isEmpty(other) == (rSlice[0] == "required_without") {
+				names = append(names, v.fieldLabel(tag))
+			}
+		}
+		if len(names) > 0 {
+			v.setMessage(rSlice[0], customMsg, jsonTag, formattedField, msgChan, strings.Join(names, " / "))
+			return true
+		}
+	}
+	return false
+}
+
+// validateNested validates the struct value, or the struct it points to, with the configuration of v.
+// Paths of the returned errors are relative to that struct.
+func (v *validation) validateNested(value reflect.Value) ValidationErrors {
+	if value.Kind() == reflect.Interface {
+		value = value.Elem()
+	}
+	if value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return nil
+		}
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return nil
+	}
+	valCtx := v.child()
+	valCtx.elemType = value.Type()
+	valCtx.elemValue = value
+	return valCtx.structValidator()
+}
+
 func decodeMultipart(r *http.Request, v any) error {
 	elType := reflect.TypeOf(v)
 	elValue := reflect.ValueOf(v)
@@ -741,8 +1509,12 @@ func decodeMultipart(r *http.Request, v any) error {
 		wg.Add(1)
 		go func(i int) {
 			defer wg.Done()
-			val, ok := elemType.Field(i).Tag.Lookup("json")
-			if !ok {
+			nameTag := "json"
+			if _, ok := elemType.Field(i).Tag.Lookup("form"); ok {
+				nameTag = "form"
+			}
+			val := fieldName(elemType.Field(i), nameTag)
+			if val == "" {
 				return
 			}
 			field := elemValue.Field(i)
@@ -841,76 +1613,80 @@ func decodeMultipart(r *http.Request, v any) error {
 }
 
 func (v *validation) getTagAndValue(lookupTag string) (tag string, value reflect.Value) {
-	for i := 0; i < v.elemType.NumField(); i++ {
-		if t, ok := v.elemType.Field(i).Tag.Lookup("json"); ok {
-			if t == lookupTag {
-				tag = t
-				value = v.elemValue.Field(i)
-				return
-			}
+	if v.mapElem != nil {
+		return lookupTag, reflect.ValueOf(v.mapElem[lookupTag])
+	}
+	for _, field := range structFields(v.elemType, v.elemValue, v.nameTag) {
+		if field.name != "" && field.name == lookupTag {
+			return field.name, field.value
 		}
 	}
 	return
 }
 
-func (v *validation) setMessage(ruleKey string, customMsg any, msgKey, field string, msgChan chan message, values ...string) {
-	if ruleKey == "empty" {
-		msgChan <- message{K: msgKey, V: customMsg}
-	} else if customMsg != "" && customMsg != nil {
-		msgChan <- message{K: msgKey, V: customMsg}
-	} else {
-		msg := v.getMessage(ruleKey)
-		if len(values) > 0 {
-			// Simple formatting, production might need pluralization handling
-			if len(values) > 1 {
-				msgChan <- message{K: msgKey, V: fmt.Sprintf(msg, field, values[0], values[1])}
-			} else {
-				msgChan <- message{K: msgKey, V: fmt.Sprintf(msg, field, values[0])}
-			}
-		} else {
-			msgChan <- message{K: msgKey, V: fmt.Sprintf(msg, field)}
-		}
+// customMessage returns the ValidateMap message override for key and rule, if any.
+func (v *validation) customMessage(key, rule string) string {
+	if v.messages == nil {
+		return ""
 	}
+	ruleName, _, _ := strings.Cut(rule, ":")
+	if msg, ok := v.messages[key+"."+ruleName]; ok {
+		return msg
+	}
+	return v.messages[key]
 }
 
-func (v *validation) generateMessage(ruleKey string, customMsg any, field string, values ...string) any {
-	if ruleKey == "empty" {
-		return ""
-	} else if customMsg != "" && customMsg != nil {
-		return customMsg
-	} else {
-		msg := v.getMessage(ruleKey)
-		if len(values) > 0 {
-			if len(values) > 1 {
-				return fmt.Sprintf(msg, field, values[0], values[1])
-			}
-			return fmt.Sprintf(msg, field, values[0])
-		}
-		return fmt.Sprintf(msg, field)
-	}
+func (v *validation) setMessage(ruleKey, customMsg, msgKey, field string, msgChan chan FieldError, values ...string) {
+	msgChan <- v.fieldError(ruleKey, customMsg, msgKey, field, values...)
 }
 
-func (v *validation) getMessage(rule string) string {
-	var message map[string]any
-	switch strings.ToLower(v.locale) {
-	case "fr":
-		message = locale.FR
-	default:
-		message = locale.EN
+// elementError builds the error of the slice element at path.
+func (v *validation) elementError(ruleKey, customMsg, msgKey, path, field string, values ...string) FieldError {
+	fe := v.fieldError(ruleKey, customMsg, msgKey, field, values...)
+	fe.Path = path
+	return fe
+}
+
+func (v *validation) fieldError(ruleKey, customMsg, msgKey, field string, values ...string) FieldError {
+	fe := FieldError{
+		Path:   msgKey,
+		Field:  msgKey,
+		Rule:   ruleName(ruleKey),
+		Params: values,
+		Locale: v.locale,
+	}
+	if customMsg != "" {
+		fe.Message = customMsg
+		return fe
 	}
+	// Simple formatting, production might need pluralization handling
+	msg := v.getMessage(ruleKey)
+	args := make([]any, 0, len(values)+1)
+	args = append(args, field)
+	for _, value := range values {
+		args = append(args, value)
+	}
+	// Custom rule messages may not reference every parameter
+	if verbs := strings.Count(msg, "%s"); verbs < len(args) {
+		args = args[:verbs]
+	}
+	fe.Message = fmt.Sprintf(msg, args...)
+	return fe
+}
 
-	if strings.Contains(rule, ".") {
-		keys := strings.SplitN(rule, ".", 2)
-		if m, ok := message[keys[0]].(map[string]string); ok {
-			if msg, ok := m[keys[1]]; ok {
-				return msg
-			}
-		}
-		return rule // Fallback
+func (v *validation) getMessage(rule string) string {
+	if msg, ok := v.rules.message(rule, v.locale); ok {
+		return msg
 	}
-	if msg, ok := message[rule].(string); ok {
+	if msg, ok := locale.Message(v.locale, rule); ok {
 		return msg
 	}
+	if v.rules.has(rule) {
+		// Custom rule registered without a message for this locale
+		if msg, ok := locale.Message(v.locale, "invalid"); ok {
+			return msg
+		}
+	}
 	return rule // Fallback
 }
 
@@ -1,9 +1,28 @@
 package valid
 
 import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"log"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
+
+	"github.com/seyramlabs/valid/locale"
 )
 
 type TestDeepStruct struct {
@@ -41,4 +60,1453 @@ func TestMain(m *testing.M) {
 	}
 
 	myLogger.Println(New().ValidateStruct(request))
+	os.Exit(m.Run())
+}
+
+func TestValidateMap(t *testing.T) {
+	elem := map[string]any{
+		"name":     "Jo",
+		"email":    "contact@mail.com",
+		"age":      17.5,
+		"userType": "guest",
+		"password": "secret",
+		"confirm":  "secrets",
+	}
+	rules := map[string]string{
+		"name":     "required|string|min:3",
+		"email":    "required|email",
+		"age":      "required|int",
+		"userType": "required|enum:admin,user",
+		"password": "required",
+		"confirm":  "required|same:password",
+		"phone":    "required>Phone please",
+	}
+	messages := map[string]string{
+		"name.min": "Name is too short",
+		"age":      "Age must be a whole number",
+	}
+
+	var errs ValidationErrors
+	if !errors.As(New().ValidateMap(elem, rules, messages), &errs) {
+		t.Fatal("expected ValidationErrors")
+	}
+	flat := errs.Flatten()
+	want := map[string]string{
+		"name":     "Name is too short",
+		"age":      "Age must be a whole number",
+		"userType": "The user type must be one of the following: admin,user.",
+		"confirm":  "The confirm and password must match.",
+		"phone":    "Phone please",
+	}
+	if len(flat) != len(want) {
+		t.Fatalf("got %d errors, want %d: %v", len(flat), len(want), errs)
+	}
+	for k, msg := range want {
+		if flat[k] != msg {
+			t.Errorf("%s: got %q, want %q", k, flat[k], msg)
+		}
+	}
+
+	if err := New().ValidateMap(map[string]any{"name": "Seyram"}, map[string]string{"name": "required|string"}); err != nil {
+		t.Errorf("expected no errors, got %v", err)
+	}
+
+	// Type rules fail on values of another kind
+	typed := map[string]string{
+		"name":  "required|string",
+		"email": "required|email",
+		"count": "required|int",
+		"code":  "alpha_numeric",
+		"rate":  "float",
+		"stock": "uint",
+		"zip":   "numeric",
+	}
+	errs = nil
+	if !errors.As(New().ValidateMap(map[string]any{"name": 42.0, "email": 7.0, "count": "abc", "code": true, "rate": "high", "stock": -2.0, "zip": true}, typed), &errs) {
+		t.Fatal("expected ValidationErrors")
+	}
+	want = map[string]string{
+		"name":  "The name must be a string.",
+		"email": "The email must be a valid email address.",
+		"count": "The count must be an integer.",
+		"code":  "The code may only contain letters and numbers.",
+		"rate":  "The rate must be a float.",
+		"stock": "The stock must be a positive integer.",
+		"zip":   "The zip must be a number.",
+	}
+	if flat := errs.Flatten(); !reflect.DeepEqual(flat, want) {
+		t.Errorf("got %v, want %v", flat, want)
+	}
+	if err := New().ValidateMap(map[string]any{"name": "Seyram", "email": "contact@mail.com", "count": 3.0, "code": "A1", "rate": 2.5, "stock": 4.0, "zip": 23.0}, typed); err != nil {
+		t.Errorf("expected no errors, got %v", err)
+	}
+}
+
+func TestTypeRulesInStructs(t *testing.T) {
+	type profile struct {
+		Emails  []string `json:"emails" validate:"email"`
+		Scores  []int    `json:"scores" validate:"int"`
+		Backup  *string  `json:"backup" validate:"email"`
+		Website *string  `json:"website" validate:"string"`
+		Age     *int     `json:"age" validate:"int"`
+	}
+	valid, invalid, empty, age := "backup@mail.com", "backup", "", 30
+	if err := New().ValidateStruct(&profile{Emails: []string{"a@mail.com", "b@mail.com"}, Scores: []int{1, 2}, Backup: &valid, Website: &empty, Age: &age}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	var errs ValidationErrors
+	if !errors.As(New().ValidateStruct(&profile{Emails: []string{"a@mail.com", "b"}, Backup: &invalid}), &errs) {
+		t.Fatal("expected ValidationErrors")
+	}
+	want := map[string]string{
+		"emails.1": "The emails (2) must be a valid email address.",
+		"backup":   "The backup must be a valid email address.",
+	}
+	if flat := errs.Flatten(); !reflect.DeepEqual(flat, want) {
+		t.Errorf("got %v, want %v", flat, want)
+	}
+}
+
+func TestValidationErrors(t *testing.T) {
+	request := &TestStruct{
+		Name:     "Seyram",
+		Contact:  &TestDeepStruct{Name: "Wood White", Phone: "++233265518694", Email: "contact@mail.com"},
+		Contacts: []*TestDeepStruct{{Name: "Wood Williams", Email: "contact@mail.com"}, {Name: "Wood White", Email: "contact@example.com"}},
+	}
+
+	var errs ValidationErrors
+	if !errors.As(New().ValidateStruct(request), &errs) {
+		t.Fatal("expected ValidationErrors")
+	}
+
+	name := errs.ByField("name")
+	if len(name) != 1 || name[0].Rule != "from" || !slices.Equal(name[0].Params, []string{"1", "5"}) || name[0].Locale != "en" {
+		t.Errorf("unexpected name error: %+v", name)
+	}
+	if contact := errs.ByField("contacts"); len(contact) != 1 || contact[0].Path != "contacts.1.email" || contact[0].Field != "email" {
+		t.Errorf("unexpected contacts error: %+v", contact)
+	}
+
+	legacy := errs.Map()
+	if _, ok := legacy["name"].(string); !ok {
+		t.Errorf("name: expected a message, got %T", legacy["name"])
+	}
+	if contact, ok := legacy["contact"].(map[string]any); !ok || contact["phone"] == nil {
+		t.Errorf("contact: expected a nested map, got %v", legacy["contact"])
+	}
+	if contacts, ok := legacy["contacts"].([]any); !ok || len(contacts) != 1 {
+		t.Errorf("contacts: expected a list with one element, got %v", legacy["contacts"])
+	}
+
+	if err := New().ValidateStruct(TestStruct{}); !errors.Is(err, ErrNotPointer) {
+		t.Errorf("expected ErrNotPointer, got %v", err)
+	}
+	if err := New().ValidateStruct(new(string)); !errors.Is(err, ErrNotStruct) {
+		t.Errorf("expected ErrNotStruct, got %v", err)
+	}
+}
+
+func TestRegisterRule(t *testing.T) {
+	v := New(&Config{Locale: LocaleFR})
+	err := v.RegisterRule("sku", func(value reflect.Value, params []string) bool {
+		prefix := "SKU-"
+		if len(params) > 0 {
+			prefix = params[0]
+		}
+		return strings.HasPrefix(value.String(), prefix)
+	}, map[string]string{
+		"en": "The %s must start with %s.",
+		"fr": "Le champ %s doit commencer par %s.",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := v.RegisterRule("bad:name", func(reflect.Value, []string) bool { return true }, nil); err == nil {
+		t.Error("expected an error for an invalid rule name")
+	}
+
+	type product struct {
+		Code  string `json:"code" validate:"required|sku:PRD-"`
+		Other string `json:"other" validate:"sku>Other code is invalid"`
+	}
+	var errs ValidationErrors
+	if !errors.As(v.ValidateStruct(&product{Code: "SKU-1", Other: "PRD-1"}), &errs) {
+		t.Fatal("expected ValidationErrors")
+	}
+	flat := errs.Flatten()
+	if flat["code"] != "Le champ code doit commencer par PRD-." {
+		t.Errorf("code: got %q", flat["code"])
+	}
+	if flat["other"] != "Other code is invalid" {
+		t.Errorf("other: got %q", flat["other"])
+	}
+	if code := errs.ByField("code"); code[0].Rule != "sku" || !slices.Equal(code[0].Params, []string{"PRD-"}) {
+		t.Errorf("unexpected code error: %+v", code[0])
+	}
+
+	if err := v.ValidateMap(map[string]any{"code": "SKU-1"}, map[string]string{"code": "sku"}); err != nil {
+		t.Errorf("expected no errors, got %v", err)
+	}
+}
+
+func TestRulePanic(t *testing.T) {
+	v := New()
+	if err := v.RegisterRule("boom", func(reflect.Value, []string) bool {
+		var m map[string]int
+		m["boom"]++
+		return true
+	}, nil); err != nil {
+		t.Fatal(err)
+	}
+	type order struct {
+		Reference string   `json:"reference" validate:"required|boom"`
+		Tags      []string `json:"tags" validate:"each:boom"`
+	}
+	err := v.ValidateStruct(&order{Reference: "A1", Tags: []string{"x"}})
+	var errs ValidationErrors
+	if !errors.Is(err, ErrPanic) || errors.As(err, &errs) {
+		t.Errorf("expected ErrPanic, got %v", err)
+	}
+	if err := v.ValidateMap(map[string]any{"reference": "A1"}, map[string]string{"reference": "boom"}); !errors.Is(err, ErrPanic) {
+		t.Errorf("expected ErrPanic, got %v", err)
+	}
+
+	handler := Middleware[order](v)(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		t.Error("next handler called")
+	}))
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"reference":"A1"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusInternalServerError || strings.Contains(rec.Body.String(), "nil map") {
+		t.Errorf("got status %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestErrorWriterHidesInternals(t *testing.T) {
+	type account struct {
+		Email string `json:"email" validate:"unique:users.email"`
+	}
+	fake := &fakeDB{rows: func(string, []driver.NamedValue) ([][]driver.Value, error) {
+		return nil, errors.New(`pq: relation "internal_users_v2" does not exist`)
+	}}
+	db := sql.OpenDB(fake)
+	defer db.Close()
+	v := New(&Config{Querier: db, Driver: DriverPostgres})
+
+	serve := func(config *MiddlewareConfig) *httptest.ResponseRecorder {
+		handler := Middleware[account](v, config)(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+			t.Error("next handler called")
+		}))
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"email":"new@mail.com"}`))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+	if rec := serve(nil); rec.Code != http.StatusInternalServerError || strings.Contains(rec.Body.String(), "internal_users_v2") {
+		t.Errorf("got status %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var got error
+	serve(&MiddlewareConfig{ErrorHandler: func(_ http.ResponseWriter, _ *http.Request, err error) { got = err }})
+	if got == nil || !strings.Contains(got.Error(), "internal_users_v2") {
+		t.Errorf("expected the query error in ErrorHandler, got %v", got)
+	}
+}
+
+func TestValidateRequestContext(t *testing.T) {
+	type signup struct {
+		Name  string `json:"name" validate:"required|string"`
+		Email string `json:"email" validate:"required|email"`
+	}
+
+	var got *signup
+	handler := New().RequestStruct(&signup{}).ValidateRequest(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, _ = FromContext[signup](r.Context())
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name":"Seyram","email":"contact@mail.com"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("got status %d: %s", rec.Code, rec.Body.String())
+	}
+	if got == nil || got.Name != "Seyram" || got.Email != "contact@mail.com" {
+		t.Errorf("unexpected struct in context: %+v", got)
+	}
+	if _, ok := FromContext[TestStruct](req.Context()); ok {
+		t.Error("expected no struct in the original request context")
+	}
+}
+
+func TestMiddlewareAndBind(t *testing.T) {
+	type signup struct {
+		Name  string `json:"name" validate:"required|string"`
+		Email string `json:"email" validate:"required|email"`
+	}
+	newRequest := func(body string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		return req
+	}
+
+	var got *signup
+	handler := Middleware[signup](New())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, _ = FromContext[signup](r.Context())
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, newRequest(`{"name":"Seyram","email":"contact@mail.com"}`))
+	if rec.Code != http.StatusOK || got == nil || got.Email != "contact@mail.com" {
+		t.Fatalf("got status %d and %+v", rec.Code, got)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, newRequest(`{"name":"Seyram"}`))
+	if rec.Code != http.StatusUnprocessableEntity || !strings.Contains(rec.Body.String(), `"email":"The email field is required."`) {
+		t.Errorf("got status %d: %s", rec.Code, rec.Body.String())
+	}
+
+	elem, err := Bind[signup](newRequest(`{"name":"Seyram","email":"contact@mail.com"}`))
+	if err != nil || elem.Name != "Seyram" {
+		t.Errorf("got %+v, %v", elem, err)
+	}
+	var decodeErr *DecodeError
+	if _, err := Bind[signup](newRequest(`{"name":`)); !errors.As(err, &decodeErr) {
+		t.Errorf("expected a DecodeError, got %v", err)
+	}
+	req := newRequest(`name=Seyram`)
+	req.Header.Set("Content-Type", "text/plain")
+	if _, err := Bind[signup](req); !errors.Is(err, ErrUnsupportedContentType) {
+		t.Errorf("expected ErrUnsupportedContentType, got %v", err)
+	}
+}
+
+func TestRegisterLocale(t *testing.T) {
+	RegisterLocale("tw", map[string]any{
+		"required": "Ehia %s.",
+		"min": map[string]string{
+			"string": "%s nsɛ nkyerɛwde %s.",
+		},
+	})
+	RegisterLocale("fr-CI", map[string]any{
+		"required": "Le champ %s est obligatoire.",
+	})
+	t.Cleanup(func() {
+		locale.Unregister("tw")
+		locale.Unregister("fr-CI")
+	})
+
+	if got := locale.Fallbacks("fr_CI"); !slices.Equal(got, []string{"fr-ci", "fr", "en"}) {
+		t.Errorf("unexpected fallbacks: %v", got)
+	}
+	if !slices.Contains(locale.Tags(), "tw") {
+		t.Errorf("tw is not registered: %v", locale.Tags())
+	}
+	if keys := locale.Keys(locale.EN); !slices.Contains(keys, "min.string") || !slices.Contains(keys, "required") {
+		t.Errorf("unexpected keys: %v", keys)
+	}
+
+	type user struct {
+		Name  string `json:"name" validate:"required"`
+		Email string `json:"email" validate:"required|email"`
+		City  string `json:"city" validate:"min:3"`
+	}
+	tests := []struct {
+		locale string
+		want   map[string]string
+	}{
+		{"tw", map[string]string{"name": "Ehia name.", "email": "Ehia email.", "city": "city nsɛ nkyerɛwde 3."}},
+		{"fr-CI", map[string]string{"name": "Le champ name est obligatoire.", "email": "Le champ email est obligatoire.", "city": "Le champ city doit comporter au moins 3 caractères."}},
+		{"ha", map[string]string{"name": "The name field is required.", "email": "The email field is required.", "city": "The city must be at least 3 characters."}},
+	}
+	for _, tt := range tests {
+		var errs ValidationErrors
+		if !errors.As(New(&Config{Locale: tt.locale}).ValidateStruct(&user{City: "Ho"}), &errs) {
+			t.Fatalf("%s: expected ValidationErrors", tt.locale)
+		}
+		for k, msg := range tt.want {
+			if got := errs.Flatten()[k]; got != msg {
+				t.Errorf("%s %s: got %q, want %q", tt.locale, k, got, msg)
+			}
+		}
+	}
+}
+
+func TestValidateRequestLocale(t *testing.T) {
+	type signup struct {
+		Name string `json:"name" validate:"required"`
+	}
+	handler := New(&Config{AcceptLanguage: true, LocaleParam: "lang"}).RequestStruct(&signup{}).ValidateRequest(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+
+	tests := []struct {
+		target, acceptLanguage, want string
+	}{
+		{"/", "", "The name field is required."},
+		{"/", "de-DE, fr-CI;q=0.8, en;q=0.9", "The name field is required."},
+		{"/", "de-DE, fr-CI;q=0.9, en;q=0.8", "Le champ name est requis."},
+		{"/", "fr;q=0, en", "The name field is required."},
+		{"/?lang=fr", "en", "Le champ name est requis."},
+		{"/?lang=xx", "fr", "Le champ name est requis."},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodPost, tt.target, strings.NewReader(`{}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept-Language", tt.acceptLanguage)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if !strings.Contains(rec.Body.String(), tt.want) {
+			t.Errorf("%s %q: got %s, want %q", tt.target, tt.acceptLanguage, rec.Body.String(), tt.want)
+		}
+	}
+}
+
+func TestComparisonRules(t *testing.T) {
+	type booking struct {
+		Guests    int                     `json:"guests" validate:"gt:0|lte:10"`
+		Price     float64                 `json:"price" validate:"gte:5"`
+		Code      string                  `json:"code" validate:"lt:5"`
+		Rooms     []string                `json:"rooms" validate:"gt:1"`
+		StartDate string                  `json:"startDate" validate:"dateonly"`
+		EndDate   string                  `json:"endDate" validate:"gt:@startDate"`
+		Adults    int                     `json:"adults" validate:"lte:@guests"`
+		Photo     *multipart.FileHeader   `json:"photo" validate:"lte:1kb"`
+		Photos    []*multipart.FileHeader `json:"photos" validate:"gt:1kb"`
+	}
+	elem := &booking{
+		Guests:    11,
+		Price:     4.5,
+		Code:      "ABCDE",
+		Rooms:     []string{"101"},
+		StartDate: "2025-03-10",
+		EndDate:   "2025-03-01",
+		Adults:    12,
+		Photo:     &multipart.FileHeader{Size: 2048},
+		Photos:    []*multipart.FileHeader{{Size: 2048}, {Size: 512}},
+	}
+
+	var errs ValidationErrors
+	if !errors.As(New().ValidateStruct(elem), &errs) {
+		t.Fatal("expected ValidationErrors")
+	}
+	want := map[string]string{
+		"guests":   "The guests must be less than or equal to 10.",
+		"price":    "The price must be greater than or equal to 5.",
+		"code":     "The code must be less than 5 characters.",
+		"rooms":    "The rooms must have more than 1 items.",
+		"endDate":  "The end date must be greater than start date.",
+		"adults":   "The adults must be less than or equal to guests.",
+		"photo":    "The photo must be less than or equal to 1 kilobytes.",
+		"photos.1": "The photos (2) must be greater than 1 kilobytes.",
+	}
+	flat := errs.Flatten()
+	if len(flat) != len(want) {
+		t.Errorf("got %d errors, want %d: %v", len(flat), len(want), flat)
+	}
+	for k, msg := range want {
+		if flat[k] != msg {
+			t.Errorf("%s: got %q, want %q", k, flat[k], msg)
+		}
+	}
+
+	if err := New().ValidateMap(map[string]any{"age": 21.0, "min": 18.0}, map[string]string{"age": "gte:@min|lt:100"}); err != nil {
+		t.Errorf("expected no errors, got %v", err)
+	}
+}
+
+func TestConditionalRules(t *testing.T) {
+	type checkout struct {
+		PaymentMethod string `json:"paymentMethod" validate:"required|enum:card,momo,cash"`
+		CardNumber    string `json:"cardNumber" validate:"required_if:paymentMethod,card|numeric"`
+		MomoNumber    string `json:"momoNumber" validate:"required_unless:paymentMethod,card,cash|phone"`
+		Change        int    `json:"change" validate:"prohibited_if:paymentMethod,card,momo"`
+		Coupon        string `json:"coupon" validate:"excluded_if:paymentMethod,cash|required"`
+		Phone         string `json:"phone" validate:"required_without:email"`
+		Email         string `json:"email" validate:"required_with:newsletter|email"`
+		Newsletter    bool   `json:"newsletter"`
+	}
+
+	var errs ValidationErrors
+	if !errors.As(New().ValidateStruct(&checkout{PaymentMethod: "card", Change: 5, Newsletter: true}), &errs) {
+		t.Fatal("expected ValidationErrors")
+	}
+	want := map[string]string{
+		"cardNumber": "The card number field is required when payment method is card.",
+		"change":     "The change field is prohibited when payment method is card,momo.",
+		"coupon":     "The coupon field is required.",
+		"phone":      "The phone field is required when email is not present.",
+		"email":      "The email field is required when newsletter is present.",
+	}
+	flat := errs.Flatten()
+	if len(flat) != len(want) {
+		t.Errorf("got %d errors, want %d: %v", len(flat), len(want), flat)
+	}
+	for k, msg := range want {
+		if flat[k] != msg {
+			t.Errorf("%s: got %q, want %q", k, flat[k], msg)
+		}
+	}
+
+	errs = nil
+	if !errors.As(New(&Config{Locale: LocaleFR}).ValidateStruct(&checkout{PaymentMethod: "momo", Coupon: "AKWAABA", Email: "contact@mail.com"}), &errs) {
+		t.Fatal("expected ValidationErrors")
+	}
+	if flat := errs.Flatten(); len(flat) != 1 || flat["momoNumber"] != "Le champ momo number est requis sauf si payment method fait partie de card,cash." {
+		t.Errorf("unexpected errors: %v", flat)
+	}
+}
+
+func TestDateRules(t *testing.T) {
+	now := time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC)
+	v := New(&Config{Now: func() time.Time { return now }})
+
+	type event struct {
+		BirthDate time.Time  `json:"birthDate" validate:"required|age_min:18|age_max:65"`
+		StartDate string     `json:"startDate" validate:"required|after:now"`
+		EndDate   *time.Time `json:"endDate" validate:"after_field:startDate|before:2026-01-01"`
+		Deadline  time.Time  `json:"deadline" validate:"gt:@endDate"`
+		Reminder  string     `json:"reminder" validate:"within:7d"`
+		Signed    string     `json:"signed" validate:"before_or_equal:today"`
+	}
+	endDate := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	elem := &event{
+		BirthDate: time.Date(2007, 6, 16, 0, 0, 0, 0, time.UTC),
+		StartDate: "2025-06-10",
+		EndDate:   &endDate,
+		Deadline:  time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC),
+		Reminder:  "2025-06-30T09:00:00Z",
+		Signed:    "2025-06-15",
+	}
+
+	var errs ValidationErrors
+	if !errors.As(v.ValidateStruct(elem), &errs) {
+		t.Fatal("expected ValidationErrors")
+	}
+	want := map[string]string{
+		"birthDate": "The birth date must correspond to an age of at least 18 years.",
+		"startDate": "The start date must be a date after now.",
+		"endDate":   "The end date must be a date after start date.",
+		"deadline":  "The deadline must be greater than end date.",
+		"reminder":  "The reminder must be within 7d of the current date.",
+	}
+	flat := errs.Flatten()
+	if len(flat) != len(want) {
+		t.Errorf("got %d errors, want %d: %v", len(flat), len(want), flat)
+	}
+	for k, msg := range want {
+		if flat[k] != msg {
+			t.Errorf("%s: got %q, want %q", k, flat[k], msg)
+		}
+	}
+
+	elem.BirthDate = time.Date(2007, 6, 15, 0, 0, 0, 0, time.UTC)
+	elem.StartDate = "2025-06-16"
+	endDate = time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	elem.Deadline = time.Date(2025, 7, 2, 0, 0, 0, 0, time.UTC)
+	elem.Reminder = "2025-06-20T09:00:00Z"
+	if err := v.ValidateStruct(elem); err != nil {
+		t.Errorf("expected no errors, got %v", err)
+	}
+}
+
+func TestEachRule(t *testing.T) {
+	type order struct {
+		Items    []string          `json:"items" validate:"required|slice:max:3|each:min:6|each:alpha>Items may only contain letters"`
+		Quantity []int             `json:"quantity" validate:"each:gt:0"`
+		Emails   []string          `json:"emails" validate:"each:required|each:email"`
+		Contacts []*TestDeepStruct `json:"contacts" validate:"each:required"`
+	}
+	elem := &order{
+		Items:    []string{"biscuit", "gari", "plantain", "kenkey1"},
+		Quantity: []int{1, -2, 3},
+		Emails:   []string{"contact@mail.com", "", "admin"},
+		Contacts: []*TestDeepStruct{{Name: "Wood White", Email: "contact@mail.com"}, nil},
+	}
+
+	var errs ValidationErrors
+	if !errors.As(New().ValidateStruct(elem), &errs) {
+		t.Fatal("expected ValidationErrors")
+	}
+	want := map[string]string{
+		"items":      "The items must not have more than 3 items.",
+		"quantity.1": "The quantity (2) must be greater than 0.",
+		"emails.1":   "The emails (2) field is required.",
+		"contacts.1": "The contacts (2) field is required.",
+	}
+	flat := errs.Flatten()
+	if len(flat) != len(want) {
+		t.Errorf("got %d errors, want %d: %v", len(flat), len(want), flat)
+	}
+	for k, msg := range want {
+		if flat[k] != msg {
+			t.Errorf("%s: got %q, want %q", k, flat[k], msg)
+		}
+	}
+
+	elem.Items = []string{"biscuit", "gari", "plantain"}
+	elem.Emails = []string{"contact@mail.com", "admin"}
+	errs = nil
+	if !errors.As(New().ValidateStruct(elem), &errs) {
+		t.Fatal("expected ValidationErrors")
+	}
+	if items := errs.ByField("items").Flatten(); len(items) != 1 || items["items.1"] != "The items (2) must be at least 6 characters." {
+		t.Errorf("unexpected items errors: %v", items)
+	}
+	if emails := errs.ByField("emails").Flatten(); len(emails) != 1 || emails["emails.1"] != "The emails (2) must be a valid email address." {
+		t.Errorf("unexpected emails errors: %v", emails)
+	}
+
+	err := New().ValidateMap(map[string]any{"tags": []any{"go", 42.0, "validation"}}, map[string]string{"tags": "each:max:5"})
+	if !errors.As(err, &errs) || errs.Flatten()["tags.2"] != "The tags (3) must not be greater than 5 characters." {
+		t.Errorf("unexpected map errors: %v", err)
+	}
+}
+
+func TestMapRules(t *testing.T) {
+	type resource struct {
+		Labels map[string]string `json:"labels" validate:"required|max:3|keys:alpha_numeric|max:8|values:required|max:10"`
+		Limits map[string]int    `json:"limits" validate:"min:1|values:gt:0"`
+	}
+	elem := &resource{
+		Labels: map[string]string{"env": "production", "team-name": "core", "owner": "", "tier": "a very long value"},
+	}
+
+	var errs ValidationErrors
+	if !errors.As(New().ValidateStruct(elem), &errs) {
+		t.Fatal("expected ValidationErrors")
+	}
+	want := map[string]string{
+		"labels": "The labels must not have more than 3 items.",
+	}
+	if flat := errs.Flatten(); !reflect.DeepEqual(flat, want) {
+		t.Errorf("got %v, want %v", flat, want)
+	}
+
+	delete(elem.Labels, "env")
+	elem.Limits = map[string]int{"cpu": 2, "memory": -1}
+	errs = nil
+	if !errors.As(New().ValidateStruct(elem), &errs) {
+		t.Fatal("expected ValidationErrors")
+	}
+	want = map[string]string{
+		"labels.owner":     "The labels (owner) field is required.",
+		"labels.team-name": "The labels key (team-name) may only contain letters and numbers.",
+		"labels.tier":      "The labels (tier) must not be greater than 10 characters.",
+		"limits.memory":    "The limits (memory) must be greater than 0.",
+	}
+	if flat := errs.Flatten(); !reflect.DeepEqual(flat, want) {
+		t.Errorf("got %v, want %v", flat, want)
+	}
+
+	err := New().ValidateMap(
+		map[string]any{"meta": map[string]any{"version": 2.0, "name": "x"}},
+		map[string]string{"meta": "keys:max:4"},
+	)
+	if !errors.As(err, &errs) || len(errs) != 1 || errs[0].Path != "meta.version" {
+		t.Errorf("unexpected map errors: %v", err)
+	}
+}
+
+func TestEmbeddedAndNestedStructs(t *testing.T) {
+	type BaseModel struct {
+		ID        string    `json:"id" validate:"required"`
+		CreatedAt time.Time `json:"createdAt" validate:"required"`
+	}
+	type audit struct {
+		Editor string `json:"editor" validate:"email"`
+	}
+	type address struct {
+		City    string `json:"city" validate:"required"`
+		Country string `json:"country" validate:"required|size:2"`
+	}
+	type account struct {
+		BaseModel
+		*audit
+		Name     string  `json:"name" validate:"required|same:id"`
+		ID       string  `json:"accountId"`
+		Address  address `json:"address"`
+		Billing  address `json:"billing" validate:"required"`
+		Shipping *address
+		Work     *address `json:"work"`
+	}
+	elem := &account{
+		BaseModel: BaseModel{ID: "base"},
+		audit:     &audit{Editor: "admin"},
+		Name:      "other",
+		Address:   address{Country: "Ghana"},
+		Work:      &address{City: "Accra"},
+	}
+
+	var errs ValidationErrors
+	if !errors.As(New().ValidateStruct(elem), &errs) {
+		t.Fatal("expected ValidationErrors")
+	}
+	want := map[string]string{
+		"createdAt":       "The created at field is required.",
+		"editor":          "The editor must be a valid email address.",
+		"name":            "The name and id must match.",
+		"address.city":    "The city field is required.",
+		"address.country": "The country must be 2 characters.",
+		"billing":         "The billing field is required.",
+		"work.country":    "The country field is required.",
+	}
+	if flat := errs.Flatten(); !reflect.DeepEqual(flat, want) {
+		t.Errorf("got %v, want %v", flat, want)
+	}
+	if m := errs.Map(); !reflect.DeepEqual(m["address"], map[string]any{"city": want["address.city"], "country": want["address.country"]}) {
+		t.Errorf("unexpected nested map: %v", m)
+	}
+}
+
+func TestNameTag(t *testing.T) {
+	type profile struct {
+		Name     string `json:"name,omitempty" xml:"fullName" validate:"required"`
+		Nickname string `json:"nickname,omitempty" xml:"nick,attr" validate:"max:3|gt:@fullName"`
+		Website  string `validate:"required"`
+		Internal string `json:"-" xml:"-" validate:"required"`
+	}
+	elem := &profile{Nickname: "kofi"}
+
+	var errs ValidationErrors
+	if !errors.As(New().ValidateStruct(elem), &errs) {
+		t.Fatal("expected ValidationErrors")
+	}
+	want := map[string]string{
+		"name":     "The name field is required.",
+		"nickname": "The nickname must not be greater than 3 characters.",
+		"Website":  "The website field is required.",
+	}
+	if flat := errs.Flatten(); !reflect.DeepEqual(flat, want) {
+		t.Errorf("json: got %v, want %v", flat, want)
+	}
+
+	errs = nil
+	if !errors.As(New(&Config{NameTag: "xml"}).ValidateStruct(elem), &errs) {
+		t.Fatal("expected ValidationErrors")
+	}
+	want = map[string]string{
+		"fullName": "The full name field is required.",
+		"nick":     "The nick must not be greater than 3 characters.",
+		"Website":  "The website field is required.",
+	}
+	if flat := errs.Flatten(); !reflect.DeepEqual(flat, want) {
+		t.Errorf("xml: got %v, want %v", flat, want)
+	}
+
+	elem.Name, elem.Nickname = "Kofi", "Kwame"
+	if err := New(&Config{NameTag: "xml"}).ValidateStruct(elem); !errors.As(err, &errs) || errs.Flatten()["nick"] != "The nick must not be greater than 3 characters." {
+		t.Errorf("unexpected errors: %v", err)
+	}
+	elem.Nickname = "Kof"
+	if err := New(&Config{NameTag: "xml"}).ValidateStruct(elem); !errors.As(err, &errs) || errs.Flatten()["nick"] != "The nick must be greater than full name." {
+		t.Errorf("unexpected errors: %v", err)
+	}
+}
+
+func TestFieldLabels(t *testing.T) {
+	for field, want := range map[string]string{
+		"userType":   "user type",
+		"userID":     "user id",
+		"HTTPServer": "http server",
+		"first_name": "first name",
+		"address2":   "address2",
+		"prénomÉtat": "prénom état",
+	} {
+		if got := formatFieldName(field); got != want {
+			t.Errorf("formatFieldName(%q) = %q, want %q", field, got, want)
+		}
+	}
+
+	type patient struct {
+		DateOfBirth string `json:"dateOfBirth" label:"Date of birth" validate:"required"`
+		WardID      string `json:"wardID" validate:"required"`
+		Discharge   string `json:"discharge" validate:"after_field:admission"`
+		Admission   string `json:"admission" label:"Admission date"`
+	}
+	elem := &patient{Discharge: "2024-01-01", Admission: "2024-02-01"}
+	RegisterLabels("fr", map[string]string{"dateOfBirth": "date de naissance", "admission": "date d’admission"})
+	t.Cleanup(func() { locale.UnregisterLabels("fr") })
+
+	tests := []struct {
+		locale string
+		want   map[string]string
+	}{
+		{"en", map[string]string{
+			"dateOfBirth": "The Date of birth field is required.",
+			"wardID":      "The ward id field is required.",
+			"discharge":   "The discharge must be a date after Admission date.",
+		}},
+		{"fr", map[string]string{
+			"dateOfBirth": "Le champ date de naissance est requis.",
+			"wardID":      "Le champ ward id est requis.",
+			"discharge":   "Le champ discharge doit être une date postérieure à date d’admission.",
+		}},
+	}
+	for _, tt := range tests {
+		var errs ValidationErrors
+		if !errors.As(New(&Config{Locale: tt.locale}).ValidateStruct(elem), &errs) {
+			t.Fatal("expected ValidationErrors")
+		}
+		if flat := errs.Flatten(); !reflect.DeepEqual(flat, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.locale, flat, tt.want)
+		}
+	}
+}
+
+func TestFlatErrors(t *testing.T) {
+	type contact struct {
+		Email string `json:"email" validate:"required|email"`
+	}
+	type customer struct {
+		Name     string     `json:"name" validate:"required"`
+		Contacts []*contact `json:"contacts" validate:"required"`
+	}
+	body := `{"contacts":[{"email":"contact@mail.com"},{"email":"admin"}]}`
+	serve := func(handler http.Handler) map[string]any {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != http.StatusUnprocessableEntity {
+			t.Fatalf("got status %d: %s", rec.Code, rec.Body.String())
+		}
+		var res struct {
+			Errors map[string]any `json:"errors"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &res); err != nil {
+			t.Fatal(err)
+		}
+		return res.Errors
+	}
+	next := http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})
+
+	want := map[string]any{
+		"name":             "The name field is required.",
+		"contacts.1.email": "The email must be a valid email address.",
+	}
+	if got := serve(New(&Config{FlatErrors: true}).RequestStruct(&customer{}).ValidateRequest(next)); !reflect.DeepEqual(got, want) {
+		t.Errorf("ValidateRequest: got %v, want %v", got, want)
+	}
+	if got := serve(Middleware[customer](New(), &MiddlewareConfig{FlatErrors: true})(next)); !reflect.DeepEqual(got, want) {
+		t.Errorf("Middleware: got %v, want %v", got, want)
+	}
+	if got := serve(Middleware[customer](New())(next)); !reflect.DeepEqual(got["contacts"], []any{map[string]any{"email": want["contacts.1.email"]}}) {
+		t.Errorf("nested: got %v", got)
+	}
+}
+
+func TestAllErrors(t *testing.T) {
+	type contact struct {
+		Email string `json:"email" validate:"required|email|max:6"`
+	}
+	type signup struct {
+		Username string   `json:"username" validate:"required|min:6|alpha"`
+		Password string   `json:"password" validate:"bail|min:8|alpha_numeric"`
+		Contact  *contact `json:"contact" validate:"required|min:1"`
+	}
+	elem := &signup{Username: "ab_1", Password: "p@ss", Contact: &contact{Email: "admin"}}
+
+	var errs ValidationErrors
+	if !errors.As(New().ValidateStruct(elem), &errs) {
+		t.Fatal("expected ValidationErrors")
+	}
+	if len(errs) != 3 {
+		t.Errorf("first failure: got %d errors: %v", len(errs), errs)
+	}
+
+	errs = nil
+	if !errors.As(New(&Config{AllErrors: true}).ValidateStruct(elem), &errs) {
+		t.Fatal("expected ValidationErrors")
+	}
+	want := map[string][]string{
+		"username":      {"The username must be at least 6 characters.", "The username may only contain letters."},
+		"password":      {"The password must be at least 8 characters."},
+		"contact.email": {"The email must be a valid email address."},
+	}
+	if got := errs.FlattenAll(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if got := errs.MapAll()["contact"]; !reflect.DeepEqual(got, map[string]any{"email": want["contact.email"]}) {
+		t.Errorf("unexpected nested errors: %v", got)
+	}
+
+	// A field failing along with its elements keeps both
+	type order struct {
+		Items []string `json:"items" validate:"slice:max:2|each:min:6"`
+	}
+	errs = nil
+	if !errors.As(New(&Config{AllErrors: true}).ValidateStruct(&order{Items: []string{"pen", "notebook", "ink"}}), &errs) {
+		t.Fatal("expected ValidationErrors")
+	}
+	wantMap := map[string]any{
+		"items":   []string{"The items must not have more than 2 items."},
+		"items.0": []string{"The items (1) must be at least 6 characters."},
+		"items.2": []string{"The items (3) must be at least 6 characters."},
+	}
+	if got := errs.MapAll(); !reflect.DeepEqual(got, wantMap) {
+		t.Errorf("got %v, want %v", got, wantMap)
+	}
+	if got := errs.Map(); len(got) != 3 || got["items.2"] != "The items (3) must be at least 6 characters." {
+		t.Errorf("unexpected map: %v", got)
+	}
+}
+
+func TestValidateStructCtx(t *testing.T) {
+	type ctxKey struct{}
+	type order struct {
+		Reference string `json:"reference" validate:"required|slow"`
+	}
+	v := New()
+	var gotTenant any
+	err := v.RegisterRuleCtx("slow", func(ctx context.Context, value reflect.Value, _ []string) bool {
+		gotTenant = ctx.Value(ctxKey{})
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(10 * time.Millisecond):
+			return value.String() != "slow"
+		}
+	}, map[string]string{"en": "The %s is slow."})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.WithValue(context.Background(), ctxKey{}, "acme")
+	var errs ValidationErrors
+	if err := v.ValidateStructCtx(ctx, &order{Reference: "slow"}); !errors.As(err, &errs) || errs.Flatten()["reference"] != "The reference is slow." {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if gotTenant != "acme" {
+		t.Errorf("rule got context value %v", gotTenant)
+	}
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := v.ValidateStructCtx(canceled, &order{Reference: "fast"}); !errors.Is(err, ErrCanceled) || !errors.Is(err, context.Canceled) {
+		t.Errorf("expected ErrCanceled, got %v", err)
+	}
+
+	handler := Middleware[order](v)(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		t.Error("next handler called")
+	}))
+	deadline, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	req := httptest.NewRequestWithContext(deadline, http.MethodPost, "/", strings.NewReader(`{"reference":"fast"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("got status %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+// fakeDB is a database/sql connector answering every query with rows.
+type fakeDB struct {
+	mu      sync.Mutex
+	queries []string
+	rows    func(query string, args []driver.NamedValue) ([][]driver.Value, error)
+}
+
+func (db *fakeDB) Connect(context.Context) (driver.Conn, error) { return fakeConn{db}, nil }
+func (db *fakeDB) Driver() driver.Driver                        { return nil }
+
+type fakeConn struct{ db *fakeDB }
+
+func (c fakeConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (c fakeConn) Close() error                        { return nil }
+func (c fakeConn) Begin() (driver.Tx, error)           { return nil, errors.New("not supported") }
+
+func (c fakeConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	c.db.mu.Lock()
+	c.db.queries = append(c.db.queries, query)
+	c.db.mu.Unlock()
+	rows, err := c.db.rows(query, args)
+	if err != nil {
+		return nil, err
+	}
+	columns := []string{"1"}
+	if len(rows) > 0 {
+		columns = make([]string, len(rows[0]))
+	}
+	return &fakeRows{rows: rows, columns: columns}, nil
+}
+
+type fakeRows struct {
+	rows    [][]driver.Value
+	columns []string
+}
+
+func (r *fakeRows) Columns() []string { return r.columns }
+func (r *fakeRows) Close() error      { return nil }
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
+
+func TestUniqueQuerier(t *testing.T) {
+	type account struct {
+		Email string `json:"email" validate:"required|unique:users.email"`
+	}
+	fake := &fakeDB{rows: func(_ string, args []driver.NamedValue) ([][]driver.Value, error) {
+		switch args[0].Value {
+		case "taken@mail.com":
+			return [][]driver.Value{{int64(1)}}, nil
+		case "broken@mail.com":
+			return nil, errors.New("connection reset")
+		}
+		return nil, nil
+	}}
+	db := sql.OpenDB(fake)
+	defer db.Close()
+	v := New(&Config{Querier: db, Driver: DriverPostgres})
+
+	if err := v.ValidateStruct(&account{Email: "new@mail.com"}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	var errs ValidationErrors
+	if err := v.ValidateStruct(&account{Email: "taken@mail.com"}); !errors.As(err, &errs) || errs.Flatten()["email"] != "The email has already been taken." {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := v.ValidateStruct(&account{Email: "broken@mail.com"}); err == nil || errors.As(err, &errs) || !strings.Contains(err.Error(), "connection reset") {
+		t.Errorf("expected the query error, got %v", err)
+	}
+	if len(fake.queries) != 3 || fake.queries[0] != `SELECT 1 FROM "users" WHERE "email"=$1 LIMIT 1` {
+		t.Errorf("unexpected queries: %q", fake.queries)
+	}
+
+	if err := New().ValidateStruct(&account{Email: "new@mail.com"}); !errors.Is(err, ErrNoDatabase) {
+		t.Errorf("expected ErrNoDatabase, got %v", err)
+	}
+	if err := New(&Config{DB: &Database{Driver: "oracle"}}).ValidateStruct(&account{Email: "new@mail.com"}); err == nil || !strings.Contains(err.Error(), "needs a DSN") {
+		t.Errorf("expected a driver error, got %v", err)
+	}
+}
+
+// fakeDriver opens connections to its fakeDB for Database.Open.
+type fakeDriver struct{ db *fakeDB }
+
+func (d fakeDriver) Open(string) (driver.Conn, error) { return fakeConn{d.db}, nil }
+
+func TestDatabaseClose(t *testing.T) {
+	type account struct {
+		Email string `json:"email" validate:"unique:users.email"`
+	}
+	fake := &fakeDB{rows: func(string, []driver.NamedValue) ([][]driver.Value, error) { return nil, nil }}
+	const driverName = "valid-close-test"
+	if !slices.Contains(sql.Drivers(), driverName) {
+		sql.Register(driverName, fakeDriver{fake})
+	}
+	v := New(&Config{DB: &Database{Driver: driverName, DSN: "test"}, Dialect: SQLite})
+	if err := v.ValidateStruct(&account{Email: "new@mail.com"}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := v.Close(); err != nil {
+		t.Errorf("unexpected close error: %v", err)
+	}
+	if err := v.ValidateStruct(&account{Email: "new@mail.com"}); err == nil || !strings.Contains(err.Error(), "database is closed") {
+		t.Errorf("expected a closed pool, got %v", err)
+	}
+	if err := New(&Config{Querier: sql.OpenDB(fake)}).Close(); err != nil {
+		t.Errorf("unexpected close error: %v", err)
+	}
+}
+
+func TestExistsRule(t *testing.T) {
+	type post struct {
+		CategoryID int    `json:"categoryId" validate:"required|exists:categories.id"`
+		AuthorID   string `json:"authorId" validate:"exists:users.id,deleted_at,NULL,role,editor"`
+	}
+	fake := &fakeDB{rows: func(_ string, args []driver.NamedValue) ([][]driver.Value, error) {
+		if args[0].Value == int64(1) || args[0].Value == "u1" {
+			return [][]driver.Value{{int64(1)}}, nil
+		}
+		return nil, nil
+	}}
+	db := sql.OpenDB(fake)
+	defer db.Close()
+
+	if err := New(&Config{Querier: db, Driver: DriverMysql}).ValidateStruct(&post{CategoryID: 1, AuthorID: "u1"}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	wantQueries := []string{
+		"SELECT 1 FROM `categories` WHERE `id`=? LIMIT 1",
+		"SELECT 1 FROM `users` WHERE `id`=? AND `deleted_at` IS NULL AND `role`=? LIMIT 1",
+	}
+	slices.Sort(fake.queries)
+	if !reflect.DeepEqual(fake.queries, wantQueries) {
+		t.Errorf("got queries %q, want %q", fake.queries, wantQueries)
+	}
+
+	var errs ValidationErrors
+	if err := New(&Config{Querier: db, Driver: DriverMysql, Locale: LocaleFR}).ValidateStruct(&post{CategoryID: 2, AuthorID: "u2"}); !errors.As(err, &errs) {
+		t.Fatalf("expected ValidationErrors, got %v", err)
+	}
+	want := map[string]string{
+		"categoryId": "Le champ category id sélectionné est invalide.",
+		"authorId":   "Le champ author id sélectionné est invalide.",
+	}
+	if flat := errs.Flatten(); !reflect.DeepEqual(flat, want) {
+		t.Errorf("got %v, want %v", flat, want)
+	}
+}
+
+func TestUniqueOptions(t *testing.T) {
+	type member struct {
+		ID       int    `json:"id"`
+		TenantID string `json:"tenantId"`
+		Email    string `json:"email" validate:"unique:users.email,ignore=@id,scope=tenant_id:@tenantId,ci"`
+		Slug     string `json:"slug" validate:"unique:pages.slug,ignore=uuid:@tenantId"`
+	}
+	var args [][]any
+	var mu sync.Mutex
+	fake := &fakeDB{rows: func(_ string, named []driver.NamedValue) ([][]driver.Value, error) {
+		values := make([]any, 0, len(named))
+		for _, arg := range named {
+			values = append(values, arg.Value)
+		}
+		mu.Lock()
+		args = append(args, values)
+		mu.Unlock()
+		if values[0] == "Taken@mail.com" {
+			return [][]driver.Value{{int64(1)}}, nil
+		}
+		return nil, nil
+	}}
+	db := sql.OpenDB(fake)
+	defer db.Close()
+	v := New(&Config{Querier: db, Driver: DriverPostgres})
+
+	if err := v.ValidateStruct(&member{ID: 7, TenantID: "acme", Email: "kofi@mail.com", Slug: "home"}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	wantQueries := []string{
+		`SELECT 1 FROM "pages" WHERE "slug"=$1 AND "uuid"<>$2 LIMIT 1`,
+		`SELECT 1 FROM "users" WHERE LOWER("email")=LOWER($1) AND "id"<>$2 AND "tenant_id"=$3 LIMIT 1`,
+	}
+	slices.Sort(fake.queries)
+	if !reflect.DeepEqual(fake.queries, wantQueries) {
+		t.Errorf("got queries %q, want %q", fake.queries, wantQueries)
+	}
+	if !slices.ContainsFunc(args, func(a []any) bool { return reflect.DeepEqual(a, []any{"kofi@mail.com", int64(7), "acme"}) }) {
+		t.Errorf("unexpected arguments: %v", args)
+	}
+
+	// Creating a member: nothing to ignore
+	fake.queries = nil
+	var errs ValidationErrors
+	if err := v.ValidateStruct(&member{TenantID: "acme", Email: "Taken@mail.com"}); !errors.As(err, &errs) || errs.Flatten()["email"] != "The email has already been taken." {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if len(fake.queries) != 1 || fake.queries[0] != `SELECT 1 FROM "users" WHERE LOWER("email")=LOWER($1) AND "tenant_id"=$2 LIMIT 1` {
+		t.Errorf("unexpected queries: %q", fake.queries)
+	}
+
+	// Without a tenant the scope is the rows without one
+	fake.queries, args = nil, nil
+	if err := v.ValidateStruct(&member{Email: "Taken@mail.com"}); !errors.As(err, &errs) || errs.Flatten()["email"] != "The email has already been taken." {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if len(fake.queries) != 1 || fake.queries[0] != `SELECT 1 FROM "users" WHERE LOWER("email")=LOWER($1) AND "tenant_id" IS NULL LIMIT 1` {
+		t.Errorf("unexpected queries: %q", fake.queries)
+	}
+	if len(args) != 1 || !reflect.DeepEqual(args[0], []any{"Taken@mail.com"}) {
+		t.Errorf("unexpected arguments: %v", args)
+	}
+
+	type invalid struct {
+		Email string `json:"email" validate:"unique:users.email,unknown"`
+	}
+	if err := v.ValidateStruct(&invalid{Email: "kofi@mail.com"}); err == nil || !strings.Contains(err.Error(), `unknown option "unknown"`) {
+		t.Errorf("expected an option error, got %v", err)
+	}
+}
+
+func TestDatabaseIdentifiers(t *testing.T) {
+	fake := &fakeDB{rows: func(string, []driver.NamedValue) ([][]driver.Value, error) { return nil, nil }}
+	db := sql.OpenDB(fake)
+	defer db.Close()
+	v := New(&Config{Querier: db, Driver: DriverPostgres, Tables: []string{"billing.invoices", "users"}})
+
+	type invoice struct {
+		Number string `json:"number" validate:"unique:billing.invoices.number"`
+	}
+	if err := v.ValidateStruct(&invoice{Number: "INV-1"}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if len(fake.queries) != 1 || fake.queries[0] != `SELECT 1 FROM "billing"."invoices" WHERE "number"=$1 LIMIT 1` {
+		t.Errorf("unexpected queries: %q", fake.queries)
+	}
+
+	type injected struct {
+		Email string `json:"email" validate:"unique:users.email;DROP TABLE users"`
+	}
+	if err := v.ValidateStruct(&injected{Email: "kofi@mail.com"}); !errors.Is(err, ErrInvalidIdentifier) {
+		t.Errorf("expected ErrInvalidIdentifier, got %v", err)
+	}
+	type other struct {
+		Email string `json:"email" validate:"exists:accounts.email"`
+	}
+	if err := v.ValidateStruct(&other{Email: "kofi@mail.com"}); !errors.Is(err, ErrTableNotAllowed) {
+		t.Errorf("expected ErrTableNotAllowed, got %v", err)
+	}
+	if len(fake.queries) != 1 {
+		t.Errorf("unexpected queries: %q", fake.queries)
+	}
+}
+
+// sqlServer is a Dialect of Microsoft SQL Server used to test custom dialects.
+type sqlServer struct{}
+
+func (sqlServer) Placeholder(n int) string { return "@p" + strconv.Itoa(n) }
+func (sqlServer) Quote(identifier string) string {
+	return "[" + identifier + "]"
+}
+func (sqlServer) ExistsQuery(table, where string) string {
+	return "SELECT TOP 1 1 FROM " + table + " WHERE " + where
+}
+
+func TestDialects(t *testing.T) {
+	type user struct {
+		Email string `json:"email" validate:"unique:dbo.users.email"`
+	}
+	tests := []struct {
+		name   string
+		config Config
+		want   string
+	}{
+		{"sqlite", Config{Driver: DriverSQLite}, `SELECT 1 FROM "dbo"."users" WHERE "email"=? LIMIT 1`},
+		{"mysql", Config{Driver: DriverMysql}, "SELECT 1 FROM `dbo`.`users` WHERE `email`=? LIMIT 1"},
+		{"custom", Config{Driver: "sqlserver", Dialect: sqlServer{}}, "SELECT TOP 1 1 FROM [dbo].[users] WHERE [email]=@p1"},
+	}
+	for _, tt := range tests {
+		fake := &fakeDB{rows: func(string, []driver.NamedValue) ([][]driver.Value, error) { return nil, nil }}
+		db := sql.OpenDB(fake)
+		tt.config.Querier = db
+		if err := New(&tt.config).ValidateStruct(&user{Email: "kofi@mail.com"}); err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.name, err)
+		}
+		if len(fake.queries) != 1 || fake.queries[0] != tt.want {
+			t.Errorf("%s: got queries %q, want %q", tt.name, fake.queries, tt.want)
+		}
+		_ = db.Close()
+	}
+
+	// The dialect of a Querier is not guessed
+	fake := &fakeDB{rows: func(string, []driver.NamedValue) ([][]driver.Value, error) { return nil, nil }}
+	db := sql.OpenDB(fake)
+	defer db.Close()
+	if err := New(&Config{Querier: db}).ValidateStruct(&user{Email: "kofi@mail.com"}); !errors.Is(err, ErrNoDialect) || len(fake.queries) != 0 {
+		t.Errorf("expected ErrNoDialect, got %v", err)
+	}
+	if _, err := (&SQLLookup{DB: db}).Exists(context.Background(), "users", "email", "kofi@mail.com"); !errors.Is(err, ErrNoDialect) {
+		t.Errorf("expected ErrNoDialect, got %v", err)
+	}
+
+	// A driver name only this test registers, keeping the registry of other runs intact
+	const testDriver = "sqlserver-dialect-test"
+	if err := New(&Config{Querier: db, Driver: testDriver}).ValidateStruct(&user{Email: "kofi@mail.com"}); !errors.Is(err, ErrNoDialect) || !strings.Contains(err.Error(), testDriver) {
+		t.Errorf("expected a dialect error, got %v", err)
+	}
+	RegisterDialect(testDriver, sqlServer{})
+	t.Cleanup(func() {
+		dialectsMu.Lock()
+		defer dialectsMu.Unlock()
+		delete(dialects, testDriver)
+	})
+	if d, ok := DialectFor(testDriver); !ok || d != (sqlServer{}) {
+		t.Errorf("got dialect %v", d)
+	}
+	if err := New(&Config{Querier: db, Driver: testDriver}).ValidateStruct(&user{Email: "kofi@mail.com"}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if want := "SELECT TOP 1 1 FROM [dbo].[users] WHERE [email]=@p1"; len(fake.queries) != 1 || fake.queries[0] != want {
+		t.Errorf("got queries %q, want %q", fake.queries, want)
+	}
+}
+
+func TestMemoryLookup(t *testing.T) {
+	type member struct {
+		ID       int    `json:"id"`
+		TenantID string `json:"tenantId"`
+		Email    string `json:"email" validate:"unique:users.email,ci,ignore=@id,scope=tenant_id:@tenantId"`
+		RoleID   int    `json:"roleId" validate:"exists:roles.id,deleted_at,NULL"`
+	}
+	lookup := NewMemoryLookup(map[string][]map[string]any{
+		"users": {
+			{"id": 1, "tenant_id": "acme", "email": "Taken@mail.com"},
+			{"id": 2, "tenant_id": "globex", "email": "other@mail.com"},
+		},
+		"roles": {{"id": 1, "deleted_at": nil}},
+	})
+	lookup.Add("roles", map[string]any{"id": 2, "deleted_at": "2024-01-01"})
+	v := New(&Config{Lookup: lookup})
+
+	tests := []struct {
+		name string
+		elem member
+		want map[string]string
+	}{
+		{"valid", member{TenantID: "acme", Email: "other@mail.com", RoleID: 1}, nil},
+		{"updating itself", member{ID: 1, TenantID: "acme", Email: "taken@mail.com", RoleID: 1}, nil},
+		{"taken case-insensitively", member{TenantID: "acme", Email: "TAKEN@mail.com", RoleID: 1}, map[string]string{"email": "The email has already been taken."}},
+		{"deleted role", member{TenantID: "acme", Email: "new@mail.com", RoleID: 2}, map[string]string{"roleId": "The selected role id is invalid."}},
+		{"missing role", member{TenantID: "acme", Email: "new@mail.com", RoleID: 3}, map[string]string{"roleId": "The selected role id is invalid."}},
+	}
+	for _, tt := range tests {
+		err := v.ValidateStruct(&tt.elem)
+		var errs ValidationErrors
+		if tt.want == nil {
+			if err != nil {
+				t.Errorf("%s: unexpected error: %v", tt.name, err)
+			}
+			continue
+		}
+		if !errors.As(err, &errs) {
+			t.Errorf("%s: expected ValidationErrors, got %v", tt.name, err)
+		} else if flat := errs.Flatten(); !reflect.DeepEqual(flat, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, flat, tt.want)
+		}
+	}
+}
+
+// oracleDialect selects the batched lookups FROM DUAL.
+type oracleDialect struct{ Dialect }
+
+func (d oracleDialect) ExistsAllQuery(queries []string) string {
+	columns := make([]string, len(queries))
+	for i, query := range queries {
+		columns[i] = "CASE WHEN EXISTS (" + query + ") THEN 1 ELSE 0 END"
+	}
+	return "SELECT " + strings.Join(columns, ", ") + " FROM DUAL"
+}
+
+func TestBatchLookups(t *testing.T) {
+	type contact struct {
+		Email string `json:"email" validate:"required|email|counted|unique:contacts.email"`
+	}
+	type addressBook struct {
+		Contacts []*contact `json:"contacts" validate:"required"`
+		Tags     []string   `json:"tags" validate:"each:unique:tags.name,ci"`
+	}
+	book := &addressBook{
+		Contacts: []*contact{{Email: "new@mail.com"}, {Email: "Taken@mail.com"}, {Email: "new@mail.com"}, {Email: "invalid"}},
+		Tags:     []string{"Go"},
+	}
+	want := map[string]string{
+		"contacts.1.email": "The email has already been taken.",
+		"contacts.3.email": "The email must be a valid email address.",
+	}
+	// The fake database compares case-insensitively, like the default MySQL collations
+	taken := func(arg driver.NamedValue) bool {
+		s, ok := arg.Value.(string)
+		return ok && strings.EqualFold(s, "taken@mail.com")
+	}
+	fake := &fakeDB{rows: func(query string, args []driver.NamedValue) ([][]driver.Value, error) {
+		if strings.HasPrefix(query, "SELECT CASE") {
+			row := make([]driver.Value, len(args))
+			for i, arg := range args {
+				row[i] = int64(0)
+				if taken(arg) {
+					row[i] = int64(1)
+				}
+			}
+			return [][]driver.Value{row}, nil
+		}
+		if slices.ContainsFunc(args, taken) {
+			return [][]driver.Value{{int64(1)}}, nil
+		}
+		return nil, nil
+	}}
+	db := sql.OpenDB(fake)
+	defer db.Close()
+	var runs atomic.Int32
+	newValidator := func(config *Config) Validator {
+		v := New(config)
+		if err := v.RegisterRule("counted", func(reflect.Value, []string) bool {
+			runs.Add(1)
+			return true
+		}, nil); err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+
+	// Identical lookups run once
+	var errs ValidationErrors
+	if err := newValidator(&Config{Querier: db, Driver: DriverPostgres}).ValidateStruct(book); !errors.As(err, &errs) || !reflect.DeepEqual(errs.Flatten(), want) {
+		t.Errorf("got %v, want %v", err, want)
+	}
+	wantQueries := []string{
+		`SELECT 1 FROM "contacts" WHERE "email"=$1 LIMIT 1`,
+		`SELECT 1 FROM "contacts" WHERE "email"=$1 LIMIT 1`,
+		`SELECT 1 FROM "tags" WHERE LOWER("name")=LOWER($1) LIMIT 1`,
+	}
+	slices.Sort(fake.queries)
+	if !reflect.DeepEqual(fake.queries, wantQueries) {
+		t.Errorf("got queries %q, want %q", fake.queries, wantQueries)
+	}
+
+	// Lookups of the same table and column share a query, single ones run as usual.
+	// Custom rules run once per value, the invalid email is not looked up.
+	fake.queries = nil
+	runs.Store(0)
+	if err := newValidator(&Config{Querier: db, Driver: DriverPostgres, BatchLookups: true}).ValidateStruct(book); !errors.As(err, &errs) || !reflect.DeepEqual(errs.Flatten(), want) {
+		t.Errorf("got %v, want %v", err, want)
+	}
+	wantQueries = []string{
+		`SELECT 1 FROM "tags" WHERE LOWER("name")=LOWER($1) LIMIT 1`,
+		`SELECT CASE WHEN EXISTS (SELECT 1 FROM "contacts" WHERE "email"=$1 LIMIT 1) THEN 1 ELSE 0 END, CASE WHEN EXISTS (SELECT 1 FROM "contacts" WHERE "email"=$2 LIMIT 1) THEN 1 ELSE 0 END`,
+	}
+	slices.Sort(fake.queries)
+	if !reflect.DeepEqual(fake.queries, wantQueries) {
+		t.Errorf("got queries %q, want %q", fake.queries, wantQueries)
+	}
+	if n := runs.Load(); n != 3 {
+		t.Errorf("custom rule ran %d times, want 3", n)
+	}
+
+	// Large batches are split to stay below the argument limits of the databases
+	fake.queries = nil
+	values := make([]any, 1200)
+	for i := range values {
+		values[i] = fmt.Sprintf("user%d@mail.com", i)
+	}
+	values[700] = "TAKEN@mail.com"
+	found, err := (&SQLLookup{DB: db, Dialect: Postgres}).ExistsAll(context.Background(), "contacts", "email", values)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fake.queries) != 3 || len(found) != len(values) || !found[700] || slices.Index(found, true) != 700 {
+		t.Errorf("got %d queries, found %d values", len(fake.queries), len(found))
+	}
+
+	// A BatchDialect controls the batched query
+	fake.queries = nil
+	found, err = (&SQLLookup{DB: db, Dialect: oracleDialect{Postgres}}).ExistsAll(context.Background(), "contacts", "email", []any{"new@mail.com", "taken@mail.com"})
+	if err != nil || !slices.Equal(found, []bool{false, true}) {
+		t.Errorf("got %v, %v", found, err)
+	}
+	wantQuery := `SELECT CASE WHEN EXISTS (SELECT 1 FROM "contacts" WHERE "email"=$1 LIMIT 1) THEN 1 ELSE 0 END, CASE WHEN EXISTS (SELECT 1 FROM "contacts" WHERE "email"=$2 LIMIT 1) THEN 1 ELSE 0 END FROM DUAL`
+	if len(fake.queries) != 1 || fake.queries[0] != wantQuery {
+		t.Errorf("got queries %q, want %q", fake.queries, wantQuery)
+	}
+
+	lookup := NewMemoryLookup(map[string][]map[string]any{
+		"tags": {{"name": "go"}},
+	})
+	book.Tags = []string{"GO", "rust", "Go"}
+	want = map[string]string{
+		"tags.0": "The tags (1) has already been taken.",
+		"tags.2": "The tags (3) has already been taken.",
+	}
+	if err := New(&Config{Lookup: lookup, BatchLookups: true}).ValidateMap(map[string]any{"tags": book.Tags}, map[string]string{"tags": "each:unique:tags.name,ci"}); !errors.As(err, &errs) || !reflect.DeepEqual(errs.Flatten(), want) {
+		t.Errorf("got %v, want %v", err, want)
+	}
 }